@@ -0,0 +1,11 @@
+package sql_utils
+
+import "regexp"
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// IsIdentifier reports whether name is a plain SQL identifier, optionally
+// qualified like "b.created_at", and so safe to interpolate into a query.
+func IsIdentifier(name string) bool {
+	return identifierPattern.MatchString(name)
+}
@@ -0,0 +1,23 @@
+package sql_utils_test
+
+import (
+	"github.com/Nuanu-com/go-utils/internal/sql_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IsIdentifier", func() {
+	It("accepts plain and qualified identifiers", func() {
+		Expect(sql_utils.IsIdentifier("task_runs")).To(BeTrue())
+		Expect(sql_utils.IsIdentifier("jobs.public_2")).To(BeTrue())
+	})
+
+	It("rejects anything else", func() {
+		Expect(sql_utils.IsIdentifier("")).To(BeFalse())
+		Expect(sql_utils.IsIdentifier("2jobs")).To(BeFalse())
+		Expect(sql_utils.IsIdentifier("a.b.c")).To(BeFalse())
+		Expect(sql_utils.IsIdentifier("jobs; DROP TABLE jobs")).To(BeFalse())
+		Expect(sql_utils.IsIdentifier(`"jobs"`)).To(BeFalse())
+	})
+})
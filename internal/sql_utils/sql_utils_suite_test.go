@@ -0,0 +1,13 @@
+package sql_utils_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSqlUtils(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SqlUtils Suite")
+}
@@ -0,0 +1,84 @@
+package text_utils
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrUnsupportedType is returned for types without a text form.
+var ErrUnsupportedType = errors.New("unsupported type")
+
+// DecodeText parses text into the addressable value. Types implementing
+// encoding.TextUnmarshaler decode themselves; strings, bools, integers and
+// floats are parsed with strconv. Any other type fails with
+// ErrUnsupportedType.
+func DecodeText(value reflect.Value, text string) error {
+	if un, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return un.UnmarshalText([]byte(text))
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		value.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(text, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(text, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(text, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(v)
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedType, value.Type())
+	}
+
+	return nil
+}
+
+// EncodeText formats value the way DecodeText parses it back. Types
+// implementing encoding.TextMarshaler encode themselves and the kinds
+// DecodeText parses are formatted with strconv.
+func EncodeText(value reflect.Value) (string, error) {
+	if !value.IsValid() {
+		return "", fmt.Errorf("%w <nil>", ErrUnsupportedType)
+	}
+
+	if m, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("%w %s", ErrUnsupportedType, value.Type())
+}
@@ -0,0 +1,69 @@
+package text_utils_test
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/internal/text_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func decode[T any](text string) (T, error) {
+	var result T
+	err := text_utils.DecodeText(reflect.ValueOf(&result).Elem(), text)
+
+	return result, err
+}
+
+var _ = Describe("DecodeText", func() {
+	It("parses basic kinds", func() {
+		Expect(decode[string]("a,b")).To(Equal("a,b"))
+		Expect(decode[bool]("true")).To(BeTrue())
+		Expect(decode[int8]("-12")).To(Equal(int8(-12)))
+		Expect(decode[uint16]("65535")).To(Equal(uint16(65535)))
+		Expect(decode[float64]("1.5")).To(Equal(1.5))
+	})
+
+	It("prefers encoding.TextUnmarshaler", func() {
+		Expect(decode[time.Time]("2024-05-01T08:00:00Z")).To(Equal(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)))
+	})
+
+	It("rejects out of range and unsupported values", func() {
+		_, err := decode[int8]("300")
+		Expect(err).To(HaveOccurred())
+
+		_, err = decode[[]int]("1")
+		Expect(err).To(MatchError(text_utils.ErrUnsupportedType))
+		Expect(err).To(MatchError("unsupported type []int"))
+	})
+})
+
+var _ = Describe("EncodeText", func() {
+	roundTrip := func(value any) {
+		text, err := text_utils.EncodeText(reflect.ValueOf(value))
+		Expect(err).NotTo(HaveOccurred())
+
+		decoded := reflect.New(reflect.TypeOf(value))
+		Expect(text_utils.DecodeText(decoded.Elem(), text)).To(Succeed())
+		Expect(decoded.Elem().Interface()).To(Equal(value))
+	}
+
+	It("formats what DecodeText parses", func() {
+		roundTrip("a,b")
+		roundTrip(true)
+		roundTrip(int8(-12))
+		roundTrip(uint16(65535))
+		roundTrip(float32(0.1))
+		roundTrip(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC))
+	})
+
+	It("rejects unsupported values", func() {
+		_, err := text_utils.EncodeText(reflect.ValueOf([]int{1}))
+		Expect(err).To(MatchError("unsupported type []int"))
+
+		_, err = text_utils.EncodeText(reflect.Value{})
+		Expect(err).To(MatchError(text_utils.ErrUnsupportedType))
+	})
+})
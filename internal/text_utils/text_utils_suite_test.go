@@ -0,0 +1,13 @@
+package text_utils_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTextUtils(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TextUtils Suite")
+}
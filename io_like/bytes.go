@@ -1,25 +1,294 @@
 package io_like
 
-import "bytes"
+import (
+	"errors"
+	"io"
+	"sync"
+)
 
+var ErrOverflow = errors.New("byte catcher is full")
+
+type OverflowPolicy int
+
+const (
+	// OverflowTruncate keeps the first MaxSize bytes and silently drops the
+	// rest, so the writer never fails.
+	OverflowTruncate OverflowPolicy = iota
+	// OverflowKeepTail keeps the last MaxSize bytes, dropping the oldest.
+	OverflowKeepTail
+	// OverflowError writes what fits and fails with ErrOverflow.
+	OverflowError
+)
+
+// ByteCatcher collects written bytes, e.g. the output of a subprocess. It is
+// safe for concurrent use. The bytes are held in a ring so a bounded catcher
+// keeping the tail never reallocates. Reads consume the caught bytes.
+//
+// The exported Buffer field is gone since writes no longer go through a
+// bytes.Buffer; read b.Buffer.Bytes() as b.Bytes() and b.Buffer.String() as
+// b.String(), or drain the catcher with Read or WriteTo.
 type ByteCatcher struct {
-	Buffer bytes.Buffer
+	// OnClose receives a snapshot of the caught bytes on the first Close,
+	// e.g. to upload them. Its error is returned by Close.
+	OnClose func(data []byte) error
+
+	mu       sync.Mutex
+	buf      []byte
+	start    int
+	size     int
+	maxSize  int
+	overflow OverflowPolicy
+	dropped  int64
+	closed   bool
 }
 
+var (
+	_ io.ReadWriteCloser = (*ByteCatcher)(nil)
+	_ io.WriterTo        = (*ByteCatcher)(nil)
+	_ io.ReaderFrom      = (*ByteCatcher)(nil)
+	_ io.StringWriter    = (*ByteCatcher)(nil)
+	_ io.ByteWriter      = (*ByteCatcher)(nil)
+)
+
+// NewByteCatcher returns an unbounded catcher.
 func NewByteCatcher() *ByteCatcher {
 	return &ByteCatcher{}
 }
 
+// NewBoundedByteCatcher returns a catcher holding at most maxSize bytes,
+// applying overflow to the bytes beyond it.
+func NewBoundedByteCatcher(maxSize int, overflow OverflowPolicy) *ByteCatcher {
+	return &ByteCatcher{maxSize: maxSize, overflow: overflow}
+}
+
+// Close makes every later write fail with io.ErrClosedPipe. The caught bytes
+// can still be read.
 func (b *ByteCatcher) Close() error {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+
+	b.closed = true
+	snapshot := b.snapshot()
+	b.mu.Unlock()
+
+	if b.OnClose != nil {
+		return b.OnClose(snapshot)
+	}
+
 	return nil
 }
 
 func (b *ByteCatcher) Write(p []byte) (n int, err error) {
-	b.Buffer.Write(p)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.write(p)
+}
+
+func (b *ByteCatcher) WriteString(s string) (n int, err error) {
+	return b.Write([]byte(s))
+}
+
+func (b *ByteCatcher) WriteByte(c byte) error {
+	_, err := b.Write([]byte{c})
+	return err
+}
+
+// ReadFrom writes everything read from r until io.EOF, returning the number
+// of bytes read.
+func (b *ByteCatcher) ReadFrom(r io.Reader) (n int64, err error) {
+	chunk := make([]byte, 32*1024)
+
+	for {
+		read, readErr := r.Read(chunk)
+		n += int64(read)
+
+		if read > 0 {
+			if _, err := b.Write(chunk[:read]); err != nil {
+				return n, err
+			}
+		}
+
+		if readErr == io.EOF {
+			return n, nil
+		}
+
+		if readErr != nil {
+			return n, readErr
+		}
+	}
+}
+
+// Read consumes the oldest caught bytes, returning io.EOF when there are
+// none.
+func (b *ByteCatcher) Read(p []byte) (n int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.size == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+
+		return 0, io.EOF
+	}
+
+	n = b.copyTo(p)
+	b.discard(n)
+
+	return n, nil
+}
+
+// WriteTo drains the caught bytes into w. The catcher stays locked while
+// writing, so w must not write back to it.
+func (b *ByteCatcher) WriteTo(w io.Writer) (n int64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for b.size > 0 {
+		segment := b.buf[b.start:min(b.start+b.size, len(b.buf))]
+		written, err := w.Write(segment)
+		n += int64(written)
+		b.discard(written)
+
+		if err != nil {
+			return n, err
+		}
+
+		if written < len(segment) {
+			return n, io.ErrShortWrite
+		}
+	}
+
+	return n, nil
+}
 
-	return len(p), nil
+func (b *ByteCatcher) write(p []byte) (n int, err error) {
+	if b.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if b.maxSize <= 0 || b.size+len(p) <= b.maxSize {
+		b.push(p)
+		return len(p), nil
+	}
+
+	room := b.maxSize - b.size
+
+	switch b.overflow {
+	case OverflowKeepTail:
+		if len(p) >= b.maxSize {
+			b.dropped += int64(b.size + len(p) - b.maxSize)
+			b.start, b.size = 0, 0
+			b.push(p[len(p)-b.maxSize:])
+		} else {
+			excess := b.size + len(p) - b.maxSize
+			b.dropped += int64(excess)
+			b.discard(excess)
+			b.push(p)
+		}
+
+		return len(p), nil
+	case OverflowError:
+		b.push(p[:room])
+		return room, ErrOverflow
+	default:
+		b.push(p[:room])
+		b.dropped += int64(len(p) - room)
+
+		return len(p), nil
+	}
 }
 
+// Bytes returns a copy of the caught bytes, safe to use while writes go on.
 func (b *ByteCatcher) Bytes() []byte {
-	return b.Buffer.Bytes()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.snapshot()
+}
+
+func (b *ByteCatcher) String() string {
+	return string(b.Bytes())
+}
+
+func (b *ByteCatcher) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.size
+}
+
+// Dropped counts the bytes lost to truncation or to keeping the tail.
+func (b *ByteCatcher) Dropped() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.dropped
+}
+
+// Reset empties the catcher, keeping its storage. A closed catcher stays
+// closed.
+func (b *ByteCatcher) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.start, b.size, b.dropped = 0, 0, 0
+}
+
+func (b *ByteCatcher) snapshot() []byte {
+	snapshot := make([]byte, b.size)
+	b.copyTo(snapshot)
+
+	return snapshot
+}
+
+// push appends p to the ring, growing it up to maxSize.
+func (b *ByteCatcher) push(p []byte) {
+	b.grow(len(p))
+
+	end := (b.start + b.size) % max(len(b.buf), 1)
+	written := copy(b.buf[end:], p)
+	copy(b.buf, p[written:])
+	b.size += len(p)
+}
+
+func (b *ByteCatcher) grow(n int) {
+	if b.size+n <= len(b.buf) {
+		return
+	}
+
+	capacity := max(2*len(b.buf), b.size+n, 64)
+
+	if b.maxSize > 0 {
+		capacity = min(capacity, b.maxSize)
+	}
+
+	buf := make([]byte, capacity)
+	b.copyTo(buf)
+	b.buf, b.start = buf, 0
+}
+
+// discard drops the n oldest bytes.
+func (b *ByteCatcher) discard(n int) {
+	b.start = (b.start + n) % len(b.buf)
+	b.size -= n
+}
+
+// copyTo copies the oldest bytes into dst.
+func (b *ByteCatcher) copyTo(dst []byte) int {
+	n := min(len(dst), b.size)
+
+	if n == 0 {
+		return 0
+	}
+
+	copied := copy(dst[:n], b.buf[b.start:])
+	copy(dst[copied:n], b.buf)
+
+	return n
 }
@@ -0,0 +1,185 @@
+package io_like_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/Nuanu-com/go-utils/io_like"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ByteCatcher", func() {
+	It("catches every write when unbounded", func() {
+		catcher := io_like.NewByteCatcher()
+
+		for idx := range 100 {
+			fmt.Fprintf(catcher, "line %d\n", idx)
+		}
+
+		Expect(catcher.Len()).To(Equal(len(catcher.String())))
+		Expect(catcher.String()).To(HavePrefix("line 0\nline 1\n"))
+		Expect(catcher.String()).To(HaveSuffix("line 99\n"))
+		Expect(catcher.Dropped()).To(BeZero())
+	})
+
+	It("truncates past the max size", func() {
+		catcher := io_like.NewBoundedByteCatcher(5, io_like.OverflowTruncate)
+
+		n, err := catcher.Write([]byte("abc"))
+		Expect(n, err).To(Equal(3))
+
+		n, err = catcher.Write([]byte("defgh"))
+		Expect(n, err).To(Equal(5))
+
+		Expect(catcher.String()).To(Equal("abcde"))
+		Expect(catcher.Dropped()).To(BeEquivalentTo(3))
+	})
+
+	It("keeps the tail", func() {
+		catcher := io_like.NewBoundedByteCatcher(5, io_like.OverflowKeepTail)
+
+		for _, chunk := range []string{"abc", "de", "fg", "h", "ijklmnop", "q"} {
+			n, err := catcher.Write([]byte(chunk))
+			Expect(n, err).To(Equal(len(chunk)))
+		}
+
+		Expect(catcher.String()).To(Equal("mnopq"))
+		Expect(catcher.Len()).To(Equal(5))
+		Expect(catcher.Dropped()).To(BeEquivalentTo(12))
+	})
+
+	It("fails on overflow", func() {
+		catcher := io_like.NewBoundedByteCatcher(4, io_like.OverflowError)
+
+		n, err := catcher.Write([]byte("abcdef"))
+		Expect(err).To(MatchError(io_like.ErrOverflow))
+		Expect(n).To(Equal(4))
+		Expect(catcher.String()).To(Equal("abcd"))
+	})
+
+	It("resets", func() {
+		catcher := io_like.NewBoundedByteCatcher(3, io_like.OverflowKeepTail)
+		catcher.Write([]byte("abcdef"))
+		catcher.Reset()
+
+		Expect(catcher.Len()).To(BeZero())
+		Expect(catcher.Dropped()).To(BeZero())
+
+		catcher.Write([]byte("xy"))
+		Expect(catcher.String()).To(Equal("xy"))
+	})
+
+	It("returns snapshots that do not change with later writes", func() {
+		catcher := io_like.NewByteCatcher()
+		catcher.Write([]byte("abc"))
+
+		snapshot := catcher.Bytes()
+		catcher.Write([]byte("def"))
+
+		Expect(string(snapshot)).To(Equal("abc"))
+	})
+
+	It("is safe for concurrent writes and reads", func() {
+		catcher := io_like.NewBoundedByteCatcher(1000, io_like.OverflowKeepTail)
+		var wg sync.WaitGroup
+
+		for range 8 {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				for range 100 {
+					catcher.Write([]byte("0123456789"))
+				}
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				for range 100 {
+					Expect(len(catcher.Bytes())).To(BeNumerically("<=", 1000))
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		Expect(catcher.String()).To(Equal(strings.Repeat("0123456789", 100)))
+		Expect(catcher.Dropped()).To(BeEquivalentTo(7000))
+	})
+
+	It("reads and drains the caught bytes", func() {
+		catcher := io_like.NewBoundedByteCatcher(6, io_like.OverflowKeepTail)
+		catcher.WriteString("abcdefgh")
+
+		chunk := make([]byte, 4)
+		n, err := catcher.Read(chunk)
+		Expect(n, err).To(Equal(4))
+		Expect(string(chunk)).To(Equal("cdef"))
+
+		Expect(catcher.WriteByte('i')).To(Succeed())
+
+		rest, err := io.ReadAll(catcher)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(rest)).To(Equal("ghi"))
+
+		_, err = catcher.Read(chunk)
+		Expect(err).To(MatchError(io.EOF))
+	})
+
+	It("writes to and reads from other streams", func() {
+		catcher := io_like.NewBoundedByteCatcher(8, io_like.OverflowKeepTail)
+
+		n, err := catcher.ReadFrom(strings.NewReader("0123456789"))
+		Expect(n, err).To(BeEquivalentTo(10))
+
+		out := &bytes.Buffer{}
+		written, err := catcher.WriteTo(out)
+		Expect(written, err).To(BeEquivalentTo(8))
+		Expect(out.String()).To(Equal("23456789"))
+		Expect(catcher.Len()).To(BeZero())
+
+		_, err = io.Copy(catcher, strings.NewReader("copied"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(catcher.String()).To(Equal("copied"))
+	})
+
+	It("rejects writes after Close", func() {
+		catcher := io_like.NewByteCatcher()
+		catcher.WriteString("report")
+
+		Expect(catcher.Close()).To(Succeed())
+
+		_, err := catcher.Write([]byte("late"))
+		Expect(err).To(MatchError(io.ErrClosedPipe))
+		Expect(catcher.WriteByte('x')).To(MatchError(io.ErrClosedPipe))
+
+		_, err = catcher.ReadFrom(strings.NewReader("late"))
+		Expect(err).To(MatchError(io.ErrClosedPipe))
+
+		Expect(catcher.String()).To(Equal("report"))
+	})
+
+	It("calls OnClose once with the caught bytes", func() {
+		uploads := []string{}
+		catcher := io_like.NewByteCatcher()
+		catcher.OnClose = func(data []byte) error {
+			uploads = append(uploads, string(data))
+			return errors.New("upload failed")
+		}
+
+		var writer io.WriteCloser = catcher
+		fmt.Fprint(writer, "export.csv")
+
+		Expect(writer.Close()).To(MatchError("upload failed"))
+		Expect(writer.Close()).To(Succeed())
+		Expect(uploads).To(Equal([]string{"export.csv"}))
+	})
+})
@@ -0,0 +1,238 @@
+package io_like
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// column is a struct field mapped to a tabular column by its column tag:
+//
+//	Amount  float64    `column:"Amount (IDR),format=%.2f"`
+//	Settled types.Date `column:"Settlement Date,required,layout=02/01/2006,layout=2006-01-02"`
+//
+// The header defaults to the field name and "-" skips the field. Embedded
+// structs without a tag are flattened. The format option renders exported
+// values; the layout options, tried in order, parse imported dates and times
+// and required fails an import whose header lacks the column. Options are
+// split on ",", so layouts containing a comma such as "Jan 2, 2006" cannot be
+// tag options and go in CSVImportOptions.DateLayouts instead.
+type column struct {
+	header   string
+	index    []int
+	format   string
+	layouts  []string
+	required bool
+}
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+func columnsOf(t reflect.Type) ([]column, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("columns of %s: expected a struct", t)
+	}
+
+	results := []column{}
+
+	for _, field := range reflect.VisibleFields(t) {
+		tag, tagged := field.Tag.Lookup("column")
+
+		if !field.IsExported() || tag == "-" || len(field.Index) > 1 && !parentsFlattened(t, field.Index) {
+			continue
+		}
+
+		if field.Anonymous && !tagged && flattened(field.Type) {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		col := column{header: name, index: field.Index}
+
+		if col.header == "" {
+			col.header = field.Name
+		}
+
+		for option := range strings.SplitSeq(options, ",") {
+			key, value, _ := strings.Cut(option, "=")
+
+			switch key {
+			case "format":
+				col.format = value
+			case "layout":
+				col.layouts = append(col.layouts, value)
+			case "required":
+				col.required = true
+			case "":
+			default:
+				return nil, fmt.Errorf("column %s: unknown option %q", field.Name, key)
+			}
+		}
+
+		results = append(results, col)
+	}
+
+	return results, nil
+}
+
+// flattened reports whether an embedded field of type t is expanded into its
+// own fields rather than used as a single column.
+func flattened(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textMarshalerType) && !t.Implements(textMarshalerType)
+}
+
+// parentsFlattened reports whether every embedded struct leading to the field
+// at index was flattened.
+func parentsFlattened(t reflect.Type, index []int) bool {
+	for _, idx := range index[:len(index)-1] {
+		field := t.Field(idx)
+		_, tagged := field.Tag.Lookup("column")
+
+		if !field.Anonymous || tagged || !flattened(field.Type) {
+			return false
+		}
+
+		t = field.Type
+
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+
+	return true
+}
+
+// field returns the column field of row, or an invalid value when an
+// embedded pointer on the way is nil.
+func (c column) field(row reflect.Value) reflect.Value {
+	for _, idx := range c.index {
+		for row.Kind() == reflect.Pointer {
+			if row.IsNil() {
+				return reflect.Value{}
+			}
+
+			row = row.Elem()
+		}
+
+		row = row.Field(idx)
+	}
+
+	return row
+}
+
+// value returns the column field of row with its pointers dereferenced, and
+// false when a pointer is nil.
+func (c column) value(row reflect.Value) (reflect.Value, bool) {
+	value := c.field(row)
+
+	for value.IsValid() && value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		value = value.Elem()
+	}
+
+	return value, value.IsValid()
+}
+
+// number reports whether the column of row renders as a plain number.
+func (c column) number(row reflect.Value) bool {
+	value, ok := c.value(row)
+
+	if !ok || c.format != "" {
+		return false
+	}
+
+	if _, ok := textMarshaler(value); ok {
+		return false
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// text renders the column of row, through its format option or its
+// encoding.TextMarshaler when it has one. Nil pointers render empty.
+func (c column) text(row reflect.Value) (string, error) {
+	value, ok := c.value(row)
+
+	if !ok {
+		return "", nil
+	}
+
+	if c.format != "" {
+		return fmt.Sprintf(c.format, value.Interface()), nil
+	}
+
+	if marshaler, ok := textMarshaler(value); ok {
+		text, err := marshaler.MarshalText()
+
+		if err != nil {
+			return "", fmt.Errorf("column %s: %w", c.header, err)
+		}
+
+		return string(text), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), nil
+	}
+
+	if stringer, ok := value.Interface().(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+
+	return fmt.Sprint(value.Interface()), nil
+}
+
+// textMarshaler also finds the marshalers declared on the pointer of a non
+// addressable value.
+func textMarshaler(value reflect.Value) (encoding.TextMarshaler, bool) {
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		return marshaler, true
+	}
+
+	if !reflect.PointerTo(value.Type()).Implements(textMarshalerType) {
+		return nil, false
+	}
+
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+
+	return pointer.Interface().(encoding.TextMarshaler), true
+}
+
+func headersOf(columns []column) []string {
+	headers := make([]string, len(columns))
+
+	for idx, col := range columns {
+		headers[idx] = col.header
+	}
+
+	return headers
+}
@@ -0,0 +1,273 @@
+package io_like
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+
+	"github.com/Nuanu-com/go-utils/maps_utils"
+)
+
+// The exporters stream rows of structs mapped by their column tags, see
+// column. They close w once everything was written, e.g. to trigger the
+// OnClose upload of a ByteCatcher, and leave it open on failure so a partial
+// export is never handed over. Use slices.Values to export a slice.
+
+const utf8BOM = "\ufeff"
+
+type CSVOptions struct {
+	// Comma separates the fields. Zero uses ','.
+	Comma rune
+	// BOM starts the file with the UTF-8 byte order mark Excel needs to
+	// detect the encoding.
+	BOM      bool
+	UseCRLF  bool
+	NoHeader bool
+}
+
+func ExportCSV[T any](w io.WriteCloser, rows iter.Seq[T], opts CSVOptions) error {
+	columns, err := columnsOf(reflect.TypeFor[T]())
+
+	if err != nil {
+		return err
+	}
+
+	if opts.BOM {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = opts.UseCRLF
+
+	if opts.Comma != 0 {
+		writer.Comma = opts.Comma
+	}
+
+	if !opts.NoHeader {
+		if err := writer.Write(headersOf(columns)); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(columns))
+
+	for row := range rows {
+		value := reflect.ValueOf(row)
+
+		for idx, col := range columns {
+			if record[idx], err = col.text(value); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// ExportJSONLines writes one JSON object per row, keyed by the column headers
+// in column order. The values are JSON encoded unless the column has a format.
+func ExportJSONLines[T any](w io.WriteCloser, rows iter.Seq[T]) error {
+	columns, err := columnsOf(reflect.TypeFor[T]())
+
+	if err != nil {
+		return err
+	}
+
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+
+	for row := range rows {
+		value := reflect.ValueOf(row)
+		object := maps_utils.NewOrderedMap[string, any]()
+
+		for _, col := range columns {
+			field, ok := col.value(value)
+
+			switch {
+			case !ok:
+				object.Set(col.header, nil)
+			case col.format != "":
+				text, _ := col.text(value)
+				object.Set(col.header, text)
+			default:
+				object.Set(col.header, field.Interface())
+			}
+		}
+
+		if err := encoder.Encode(object); err != nil {
+			return err
+		}
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+type XLSXOptions struct {
+	// Sheet names the worksheet. Empty uses "Sheet1".
+	Sheet    string
+	NoHeader bool
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// ExportXLSX writes a single sheet workbook without styles. Numbers are
+// written as numeric cells, every other value as an inline string.
+func ExportXLSX[T any](w io.WriteCloser, rows iter.Seq[T], opts XLSXOptions) error {
+	columns, err := columnsOf(reflect.TypeFor[T]())
+
+	if err != nil {
+		return err
+	}
+
+	sheet := opts.Sheet
+
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+
+	// Excel rejects longer sheet names.
+	sheet = string([]rune(sheet)[:min(len([]rune(sheet)), 31)])
+
+	archive := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xmlEscape(sheet))},
+	}
+
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return err
+		}
+	}
+
+	file, err := archive.Create("xl/worksheets/sheet1.xml")
+
+	if err != nil {
+		return err
+	}
+
+	sheetXML := bufio.NewWriter(file)
+	sheetXML.WriteString(xlsxSheetStart)
+
+	line := 0
+
+	if !opts.NoHeader {
+		line++
+		writeXLSXRow(sheetXML, line, len(columns), func(idx int) (string, bool) {
+			return columns[idx].header, false
+		})
+	}
+
+	for row := range rows {
+		value := reflect.ValueOf(row)
+		texts := make([]string, len(columns))
+
+		for idx, col := range columns {
+			if texts[idx], err = col.text(value); err != nil {
+				return err
+			}
+		}
+
+		line++
+		writeXLSXRow(sheetXML, line, len(columns), func(idx int) (string, bool) {
+			return texts[idx], columns[idx].number(value)
+		})
+	}
+
+	sheetXML.WriteString(xlsxSheetEnd)
+
+	if err := errors.Join(sheetXML.Flush(), archive.Close()); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+func writeXLSXRow(out *bufio.Writer, line int, size int, cell func(idx int) (text string, number bool)) {
+	fmt.Fprintf(out, `<row r="%d">`, line)
+
+	for idx := range size {
+		text, number := cell(idx)
+		ref := fmt.Sprintf("%s%d", xlsxColumnName(idx), line)
+
+		if number {
+			fmt.Fprintf(out, `<c r="%s"><v>%s</v></c>`, ref, text)
+		} else {
+			fmt.Fprintf(out, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(text))
+		}
+	}
+
+	out.WriteString(`</row>`)
+}
+
+// xlsxColumnName returns the letters of the zero based column, e.g. 27 is AB.
+func xlsxColumnName(idx int) string {
+	name := ""
+
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = string(rune('A'+(idx-1)%26)) + name
+	}
+
+	return name
+}
+
+func xmlEscape(text string) string {
+	escaped := &strings.Builder{}
+	xml.EscapeText(escaped, []byte(text))
+
+	return escaped.String()
+}
@@ -0,0 +1,173 @@
+package io_like_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/io_like"
+	"github.com/Nuanu-com/go-utils/types"
+	"github.com/google/uuid"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type rupiah int64
+
+func (r rupiah) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "Rp%d.%02d", r/100, r%100), nil
+}
+
+type audit struct {
+	CreatedBy string `column:"Created By"`
+}
+
+type bookingRow struct {
+	ID       uuid.UUID          `column:"Booking ID"`
+	Guest    string             `column:"Guest"`
+	Date     types.Date         `column:"Check In"`
+	Arrival  types.TimeOnly     `column:"Arrival"`
+	PaidAt   types.LocalTime    `column:"Paid At"`
+	Nights   int                `column:"Nights"`
+	Rate     float64            `column:"Rate,format=%.2f"`
+	Total    rupiah             `column:"Total"`
+	Note     types.Null[string] `column:"Note"`
+	Internal string             `column:"-"`
+	audit
+}
+
+type failingRow struct {
+	Value failingText
+}
+
+type failingText struct{}
+
+func (failingText) MarshalText() ([]byte, error) {
+	return nil, errors.New("cannot render")
+}
+
+var _ = Describe("Exporters", func() {
+	paidAt := types.LocalTime(time.Date(2024, 5, 1, 9, 30, 0, 0, time.Local))
+	rows := []bookingRow{
+		{
+			ID:      uuid.MustParse("8f8b7a34-5c1e-4f0e-9a51-3f8a4c9d2e10"),
+			Guest:   "Ayu, \"VIP\"",
+			Date:    types.MustParseDate("2024-05-01"),
+			Arrival: types.NewTimeOnly(14, 0, 0),
+			PaidAt:  paidAt,
+			Nights:  3,
+			Rate:    1250.5,
+			Total:   375150,
+			Note:    types.NewNull("late check in", true),
+			audit:   audit{CreatedBy: "ops"},
+		},
+		{
+			ID:      uuid.MustParse("0b1c2d3e-4f50-4617-8899-aabbccddeeff"),
+			Guest:   "Budi",
+			Date:    types.MustParseDate("2024-05-02"),
+			Arrival: types.NewTimeOnly(9, 5, 0),
+			PaidAt:  paidAt,
+			Nights:  1,
+			Rate:    800,
+			Total:   80000,
+		},
+	}
+
+	It("exports CSV with tagged headers and text marshalers", func() {
+		catcher := io_like.NewByteCatcher()
+
+		Expect(io_like.ExportCSV(catcher, slices.Values(rows), io_like.CSVOptions{})).To(Succeed())
+
+		paid, _ := paidAt.MarshalText()
+		Expect(catcher.String()).To(Equal(
+			"Booking ID,Guest,Check In,Arrival,Paid At,Nights,Rate,Total,Note,Created By\n" +
+				"8f8b7a34-5c1e-4f0e-9a51-3f8a4c9d2e10,\"Ayu, \"\"VIP\"\"\",2024-05-01,14:00:00," + string(paid) + ",3,1250.50,Rp3751.50,late check in,ops\n" +
+				"0b1c2d3e-4f50-4617-8899-aabbccddeeff,Budi,2024-05-02,09:05:00," + string(paid) + ",1,800.00,Rp800.00,,\n",
+		))
+	})
+
+	It("exports CSV for Excel with a delimiter and BOM", func() {
+		catcher := io_like.NewByteCatcher()
+		opts := io_like.CSVOptions{Comma: ';', BOM: true, UseCRLF: true}
+
+		Expect(io_like.ExportCSV(catcher, slices.Values(rows[1:]), opts)).To(Succeed())
+
+		lines := strings.Split(catcher.String(), "\r\n")
+		Expect(lines[0]).To(HavePrefix("\ufeffBooking ID;Guest;Check In"))
+		Expect(lines[1]).To(HavePrefix("0b1c2d3e-4f50-4617-8899-aabbccddeeff;Budi;2024-05-02"))
+	})
+
+	It("closes the sink once the export is complete", func() {
+		uploaded := ""
+		catcher := io_like.NewByteCatcher()
+		catcher.OnClose = func(data []byte) error {
+			uploaded = string(data)
+			return nil
+		}
+
+		Expect(io_like.ExportCSV(catcher, slices.Values(rows), io_like.CSVOptions{NoHeader: true})).To(Succeed())
+		Expect(uploaded).To(HavePrefix("8f8b7a34"))
+
+		_, err := catcher.Write([]byte("late"))
+		Expect(err).To(MatchError(io.ErrClosedPipe))
+	})
+
+	It("leaves the sink open when a value fails to render", func() {
+		catcher := io_like.NewByteCatcher()
+
+		err := io_like.ExportCSV(catcher, slices.Values([]failingRow{{}}), io_like.CSVOptions{})
+		Expect(err).To(MatchError(ContainSubstring("cannot render")))
+
+		_, err = catcher.Write([]byte("still open"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("exports JSON lines in column order", func() {
+		catcher := io_like.NewByteCatcher()
+
+		Expect(io_like.ExportJSONLines(catcher, slices.Values(rows))).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(catcher.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[1]).To(HavePrefix(`{"Booking ID":"0b1c2d3e-4f50-4617-8899-aabbccddeeff","Guest":"Budi","Check In":"2024-05-02","Arrival":"09:05:00",`))
+		Expect(lines[1]).To(HaveSuffix(`"Nights":1,"Rate":"800.00","Total":"Rp800.00","Note":null,"Created By":""}`))
+	})
+
+	It("exports a minimal XLSX workbook", func() {
+		catcher := io_like.NewByteCatcher()
+
+		Expect(io_like.ExportXLSX(catcher, slices.Values(rows), io_like.XLSXOptions{Sheet: "Bookings & Co"})).To(Succeed())
+
+		archive, err := zip.NewReader(bytes.NewReader(catcher.Bytes()), int64(catcher.Len()))
+		Expect(err).NotTo(HaveOccurred())
+
+		parts := map[string]string{}
+
+		for _, file := range archive.File {
+			reader, err := file.Open()
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := io.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			parts[file.Name] = string(content)
+		}
+
+		Expect(parts).To(HaveKey("[Content_Types].xml"))
+		Expect(parts).To(HaveKey("_rels/.rels"))
+		Expect(parts["xl/workbook.xml"]).To(ContainSubstring(`<sheet name="Bookings &amp; Co"`))
+
+		sheet := parts["xl/worksheets/sheet1.xml"]
+		Expect(sheet).To(ContainSubstring(`<c r="A1" t="inlineStr"><is><t xml:space="preserve">Booking ID</t></is></c>`))
+		Expect(sheet).To(ContainSubstring(`<c r="B2" t="inlineStr"><is><t xml:space="preserve">Ayu, &#34;VIP&#34;</t></is></c>`))
+		Expect(sheet).To(ContainSubstring(`<c r="F2"><v>3</v></c>`))
+		Expect(sheet).To(ContainSubstring(`<c r="G2" t="inlineStr"><is><t xml:space="preserve">1250.50</t></is></c>`))
+		Expect(sheet).To(ContainSubstring(`<row r="3">`))
+	})
+})
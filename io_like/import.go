@@ -0,0 +1,344 @@
+package io_like
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/internal/text_utils"
+	"github.com/Nuanu-com/go-utils/types"
+	"github.com/google/uuid"
+)
+
+type CSVImportOptions struct {
+	// Comma separates the fields. Zero uses ','.
+	Comma rune
+	// DateLayouts are tried after the layout options of a column and before
+	// the standard layouts of its type. Layouts containing a comma must be
+	// given here since they cannot be column tag options.
+	DateLayouts []string
+	// TrimSpace trims the headers and values.
+	TrimSpace bool
+}
+
+// ColumnError is a value that could not be decoded into its column.
+type ColumnError struct {
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *ColumnError) Error() string {
+	return fmt.Sprintf("column %s: cannot decode %q: %s", e.Column, e.Value, e.Err.Error())
+}
+
+func (e *ColumnError) Unwrap() error {
+	return e.Err
+}
+
+// RowError groups the errors of one record, mostly *ColumnError.
+type RowError struct {
+	Line int
+	Errs []error
+}
+
+func (e *RowError) Error() string {
+	messages := make([]string, len(e.Errs))
+
+	for idx, err := range e.Errs {
+		messages[idx] = err.Error()
+	}
+
+	return fmt.Sprintf("line %d: %s", e.Line, strings.Join(messages, "; "))
+}
+
+func (e *RowError) Unwrap() []error {
+	return e.Errs
+}
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	dateType      = reflect.TypeFor[types.Date]()
+	localTimeType = reflect.TypeFor[types.LocalTime]()
+	timeOnlyType  = reflect.TypeFor[types.TimeOnly]()
+	uuidType      = reflect.TypeFor[uuid.UUID]()
+)
+
+// standardLayouts are tried last for each date and time type.
+var standardLayouts = map[reflect.Type][]string{
+	timeType:      {time.RFC3339, time.DateTime, time.DateOnly},
+	dateType:      {types.StandardDateFormat},
+	localTimeType: {types.LocalTimeFormat, types.LocalTimeFormatWithoutZ},
+	timeOnlyType:  {types.HourMinuteSecondLayout, types.HourMinuteLayout},
+}
+
+// ImportCSV streams the records of r decoded into T by header name, matching
+// the column tags of T case insensitively. Columns missing from the header
+// are left zero and extra columns are ignored. Empty values leave the field
+// zero, so a types.Null is invalid and a pointer nil.
+//
+// A record that fails to decode yields what could be decoded with a
+// *RowError listing every failed column, and the import goes on. A missing
+// header or required column and read failures yield an error and end the
+// import.
+func ImportCSV[T any](r io.Reader, opts CSVImportOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		columns, err := columnsOf(reflect.TypeFor[T]())
+
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = opts.TrimSpace
+		reader.ReuseRecord = true
+
+		if opts.Comma != 0 {
+			reader.Comma = opts.Comma
+		}
+
+		header, err := reader.Read()
+
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("csv import: missing header")
+			}
+
+			yield(zero, err)
+			return
+		}
+
+		positions, err := headerPositions(header, columns)
+
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for {
+			record, err := reader.Read()
+
+			if err == io.EOF {
+				return
+			}
+
+			var parseErr *csv.ParseError
+
+			if errors.As(err, &parseErr) {
+				if !yield(zero, &RowError{Line: parseErr.StartLine, Errs: []error{parseErr.Err}}) {
+					return
+				}
+
+				continue
+			}
+
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			line, _ := reader.FieldPos(0)
+			row, rowErr := decodeRecord[T](record, line, columns, positions, opts)
+
+			if !yield(row, rowErr) {
+				return
+			}
+		}
+	}
+}
+
+// headerPositions returns the record position of every column, -1 when the
+// header lacks it.
+func headerPositions(header []string, columns []column) ([]int, error) {
+	names := map[string]int{}
+
+	for idx, name := range header {
+		if idx == 0 {
+			name = strings.TrimPrefix(name, utf8BOM)
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if _, found := names[name]; !found {
+			names[name] = idx
+		}
+	}
+
+	positions := make([]int, len(columns))
+	missing := []string{}
+
+	for idx, col := range columns {
+		position, found := names[strings.ToLower(col.header)]
+
+		if !found {
+			position = -1
+
+			if col.required {
+				missing = append(missing, col.header)
+			}
+		}
+
+		positions[idx] = position
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("csv import: missing required columns %s", strings.Join(missing, ", "))
+	}
+
+	return positions, nil
+}
+
+func decodeRecord[T any](record []string, line int, columns []column, positions []int, opts CSVImportOptions) (T, error) {
+	var row T
+
+	target := reflect.ValueOf(&row).Elem()
+	errs := []error{}
+
+	for idx, col := range columns {
+		position := positions[idx]
+
+		if position < 0 || position >= len(record) {
+			continue
+		}
+
+		text := record[position]
+
+		if opts.TrimSpace {
+			text = strings.TrimSpace(text)
+		}
+
+		if text == "" {
+			continue
+		}
+
+		layouts := slices.Concat(col.layouts, opts.DateLayouts)
+
+		if err := decodeText(settable(target, col.index), text, layouts); err != nil {
+			errs = append(errs, &ColumnError{Column: col.header, Value: text, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return row, &RowError{Line: line, Errs: errs}
+	}
+
+	return row, nil
+}
+
+// settable returns the field at index, allocating the embedded pointers on
+// the way.
+func settable(value reflect.Value, index []int) reflect.Value {
+	for _, idx := range index {
+		for value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(idx)
+	}
+
+	return value
+}
+
+func decodeText(field reflect.Value, text string, layouts []string) error {
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+
+		if err := decodeText(value.Elem(), text, layouts); err != nil {
+			return err
+		}
+
+		field.Set(value)
+
+		return nil
+	}
+
+	// types.Null and sql.Null decode into V so V gets the layouts too.
+	if valid, v := nullFields(field); valid.IsValid() {
+		if err := decodeText(v, text, layouts); err != nil {
+			return err
+		}
+
+		valid.SetBool(true)
+
+		return nil
+	}
+
+	if standard, found := standardLayouts[field.Type()]; found {
+		return decodeTime(field, text, slices.Concat(layouts, standard))
+	}
+
+	if field.Type() == uuidType {
+		value := types.UUIDConverter(text)
+
+		if !value.IsValid() {
+			return errors.New("invalid UUID")
+		}
+
+		field.Set(value)
+
+		return nil
+	}
+
+	return text_utils.DecodeText(field, text)
+}
+
+// nullFields returns the Valid and V fields of a nullable struct.
+func nullFields(field reflect.Value) (reflect.Value, reflect.Value) {
+	if field.Kind() != reflect.Struct {
+		return reflect.Value{}, reflect.Value{}
+	}
+
+	valid := field.FieldByName("Valid")
+	v := field.FieldByName("V")
+
+	if !valid.IsValid() || valid.Kind() != reflect.Bool || !v.IsValid() || !v.CanSet() {
+		return reflect.Value{}, reflect.Value{}
+	}
+
+	return valid, v
+}
+
+func decodeTime(field reflect.Value, text string, layouts []string) error {
+	location := time.UTC
+
+	if field.Type() == localTimeType {
+		location = time.Local
+	}
+
+	for _, layout := range layouts {
+		parsed, err := time.ParseInLocation(layout, text, location)
+
+		if err != nil {
+			continue
+		}
+
+		switch field.Type() {
+		case dateType:
+			field.Set(reflect.ValueOf(types.DatetimeToDate(parsed)))
+		case localTimeType:
+			field.Set(reflect.ValueOf(types.LocalTime(parsed)))
+		case timeOnlyType:
+			field.Set(reflect.ValueOf(types.DatetimeToTimeOnly(parsed)))
+		default:
+			field.Set(reflect.ValueOf(parsed))
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("does not match the layouts %s", strings.Join(layouts, ", "))
+}
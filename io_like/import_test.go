@@ -0,0 +1,168 @@
+package io_like_test
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/io_like"
+	"github.com/Nuanu-com/go-utils/types"
+	"github.com/google/uuid"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type settlementRow struct {
+	Reference uuid.UUID              `column:"Reference,required"`
+	Date      types.Date             `column:"Settlement Date,layout=02/01/2006"`
+	Cutoff    types.TimeOnly         `column:"Cutoff"`
+	Amount    float64                `column:"Amount,required"`
+	Fee       types.Null[int]        `column:"Fee"`
+	Value     types.Null[types.Date] `column:"Value Date,layout=02/01/2006"`
+	Merchant  *string                `column:"Merchant"`
+	Settled   bool                   `column:"Settled"`
+	PostedAt  time.Time              `column:"Posted At"`
+	Ignored   string                 `column:"-"`
+}
+
+func collect[T any](seq func(func(T, error) bool)) ([]T, []error) {
+	rows := []T{}
+	errs := []error{}
+
+	for row, err := range seq {
+		rows = append(rows, row)
+		errs = append(errs, err)
+	}
+
+	return rows, errs
+}
+
+var _ = Describe("ImportCSV", func() {
+	It("decodes the records by header name", func() {
+		input := "\ufeff" + "settlement date,REFERENCE,Amount,Fee,Value Date,Merchant,Cutoff,Settled,Posted At,Extra\n" +
+			"01/05/2024,8f8b7a34-5c1e-4f0e-9a51-3f8a4c9d2e10,1500.25,25,02/05/2024,Warung Ayu,14:30,true,2024-05-01 10:00:00,x\n" +
+			"2024-05-03,0b1c2d3e-4f50-4617-8899-aabbccddeeff,99,,,,23:59:59,false,2024-05-03T10:00:00Z,y\n"
+
+		rows, errs := collect(io_like.ImportCSV[settlementRow](strings.NewReader(input), io_like.CSVImportOptions{}))
+
+		Expect(errs).To(Equal([]error{nil, nil}))
+		Expect(rows).To(HaveLen(2))
+
+		first := rows[0]
+		Expect(first.Reference).To(Equal(uuid.MustParse("8f8b7a34-5c1e-4f0e-9a51-3f8a4c9d2e10")))
+		Expect(first.Date).To(Equal(types.MustParseDate("2024-05-01")))
+		Expect(first.Cutoff).To(Equal(types.NewTimeOnly(14, 30, 0)))
+		Expect(first.Amount).To(Equal(1500.25))
+		Expect(first.Fee).To(Equal(types.NewNull(25, true)))
+		Expect(first.Value).To(Equal(types.NewNull(types.MustParseDate("2024-05-02"), true)))
+		Expect(*first.Merchant).To(Equal("Warung Ayu"))
+		Expect(first.Settled).To(BeTrue())
+		Expect(first.PostedAt).To(Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+
+		second := rows[1]
+		Expect(second.Date).To(Equal(types.MustParseDate("2024-05-03")))
+		Expect(second.Fee.Valid).To(BeFalse())
+		Expect(second.Value.Valid).To(BeFalse())
+		Expect(second.Merchant).To(BeNil())
+		Expect(second.Cutoff).To(Equal(types.NewTimeOnly(23, 59, 59)))
+	})
+
+	It("reports every failed column and keeps going", func() {
+		input := "Reference,Amount,Settlement Date,Fee\n" +
+			"not-a-uuid,abc,31/02/2024,1\n" +
+			"8f8b7a34-5c1e-4f0e-9a51-3f8a4c9d2e10,10,01/05/2024,x\n" +
+			"0b1c2d3e-4f50-4617-8899-aabbccddeeff,20,,\n"
+
+		rows, errs := collect(io_like.ImportCSV[settlementRow](strings.NewReader(input), io_like.CSVImportOptions{}))
+
+		Expect(rows).To(HaveLen(3))
+
+		var rowErr *io_like.RowError
+		Expect(errors.As(errs[0], &rowErr)).To(BeTrue())
+		Expect(rowErr.Line).To(Equal(2))
+		Expect(rowErr.Errs).To(HaveLen(3))
+
+		columns := []string{}
+
+		for _, err := range rowErr.Errs {
+			var columnErr *io_like.ColumnError
+			Expect(errors.As(err, &columnErr)).To(BeTrue())
+			columns = append(columns, columnErr.Column)
+		}
+
+		Expect(columns).To(Equal([]string{"Reference", "Settlement Date", "Amount"}))
+
+		Expect(errs[1]).To(MatchError(ContainSubstring(`line 3: column Fee: cannot decode "x"`)))
+		Expect(rows[1].Amount).To(Equal(10.0))
+
+		Expect(errs[2]).NotTo(HaveOccurred())
+		Expect(rows[2].Amount).To(Equal(20.0))
+	})
+
+	It("tries the configured date layouts", func() {
+		input := "Reference;Amount;Settlement Date;Posted At\n" +
+			" 8f8b7a34-5c1e-4f0e-9a51-3f8a4c9d2e10 ; 1 ; May 1, 2024 ; 01-05-2024 08:00\n"
+
+		rows, errs := collect(io_like.ImportCSV[settlementRow](strings.NewReader(input), io_like.CSVImportOptions{
+			Comma:       ';',
+			TrimSpace:   true,
+			DateLayouts: []string{"Jan 2, 2006", "02-01-2006 15:04"},
+		}))
+
+		Expect(errs).To(Equal([]error{nil}))
+		Expect(rows[0].Date).To(Equal(types.MustParseDate("2024-05-01")))
+		Expect(rows[0].PostedAt).To(Equal(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)))
+	})
+
+	It("fails on missing required columns", func() {
+		rows, errs := collect(io_like.ImportCSV[settlementRow](strings.NewReader("Reference,Fee\n"), io_like.CSVImportOptions{}))
+
+		Expect(rows).To(HaveLen(1))
+		Expect(errs[0]).To(MatchError("csv import: missing required columns Amount"))
+
+		_, errs = collect(io_like.ImportCSV[settlementRow](strings.NewReader(""), io_like.CSVImportOptions{}))
+		Expect(errs[0]).To(MatchError("csv import: missing header"))
+	})
+
+	It("reports malformed records and stops when the consumer does", func() {
+		input := "Reference,Amount\n" +
+			"8f8b7a34-5c1e-4f0e-9a51-3f8a4c9d2e10,\"1\n" +
+			"0b1c2d3e-4f50-4617-8899-aabbccddeeff,2\n"
+
+		yielded := 0
+
+		for _, err := range io_like.ImportCSV[settlementRow](strings.NewReader(input), io_like.CSVImportOptions{}) {
+			yielded++
+
+			var rowErr *io_like.RowError
+			Expect(errors.As(err, &rowErr)).To(BeTrue())
+			Expect(rowErr.Line).To(Equal(2))
+			break
+		}
+
+		Expect(yielded).To(Equal(1))
+	})
+
+	It("round trips the exported CSV", func() {
+		catcher := io_like.NewByteCatcher()
+		fee := types.NewNull(5, true)
+		merchant := "Toko"
+
+		exported := []settlementRow{{
+			Reference: uuid.MustParse("8f8b7a34-5c1e-4f0e-9a51-3f8a4c9d2e10"),
+			Date:      types.MustParseDate("2024-05-01"),
+			Cutoff:    types.NewTimeOnly(8, 0, 0),
+			Amount:    12.5,
+			Fee:       fee,
+			Merchant:  &merchant,
+			PostedAt:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		}}
+
+		Expect(io_like.ExportCSV(catcher, func(yield func(settlementRow) bool) { yield(exported[0]) }, io_like.CSVOptions{BOM: true})).To(Succeed())
+
+		rows, errs := collect(io_like.ImportCSV[settlementRow](catcher, io_like.CSVImportOptions{}))
+		Expect(errs).To(Equal([]error{nil}))
+		Expect(rows).To(Equal(exported))
+	})
+})
@@ -0,0 +1,13 @@
+package io_like_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestIoLike(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IoLike Suite")
+}
@@ -0,0 +1,239 @@
+package io_like
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// LineWriter calls OnLine for every complete line written, without its "\n"
+// or "\r\n" ending. A trailing partial line is held until the next write,
+// Flush or Close. Lines are delivered one at a time in the order they were
+// written: a Write while OnLine runs on another goroutine queues its lines
+// for that goroutine and returns. OnLine is called outside the lock, so it
+// may write back to the LineWriter; those lines follow the queued ones.
+type LineWriter struct {
+	mu         sync.Mutex
+	onLine     func(line string)
+	pending    []byte
+	queue      []string
+	delivering bool
+}
+
+func NewLineWriter(onLine func(line string)) *LineWriter {
+	return &LineWriter{onLine: onLine}
+}
+
+func (w *LineWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+
+	w.pending = append(w.pending, p...)
+	consumed := 0
+
+	for {
+		idx := bytes.IndexByte(w.pending[consumed:], '\n')
+
+		if idx < 0 {
+			break
+		}
+
+		w.queue = append(w.queue, string(bytes.TrimSuffix(w.pending[consumed:consumed+idx], []byte("\r"))))
+		consumed += idx + 1
+	}
+
+	// Move the partial line to the front to reuse the storage.
+	w.pending = append(w.pending[:0], w.pending[consumed:]...)
+	w.mu.Unlock()
+
+	w.deliver()
+
+	return len(p), nil
+}
+
+// Flush emits the pending partial line, if any.
+func (w *LineWriter) Flush() error {
+	w.mu.Lock()
+
+	if len(w.pending) > 0 {
+		w.queue = append(w.queue, string(bytes.TrimSuffix(w.pending, []byte("\r"))))
+		w.pending = nil
+	}
+
+	w.mu.Unlock()
+
+	w.deliver()
+
+	return nil
+}
+
+func (w *LineWriter) Close() error {
+	return w.Flush()
+}
+
+// deliver hands the queued lines to OnLine unless another goroutine already
+// does, which then delivers them too.
+func (w *LineWriter) deliver() {
+	w.mu.Lock()
+
+	if w.delivering {
+		w.mu.Unlock()
+		return
+	}
+
+	w.delivering = true
+	w.mu.Unlock()
+
+	done := false
+
+	// A panicking OnLine must not stop later writes from delivering.
+	defer func() {
+		if !done {
+			w.mu.Lock()
+			w.delivering = false
+			w.mu.Unlock()
+		}
+	}()
+
+	for {
+		w.mu.Lock()
+		lines := w.queue
+		w.queue = nil
+
+		if len(lines) == 0 {
+			w.delivering = false
+			done = true
+			w.mu.Unlock()
+
+			return
+		}
+
+		w.mu.Unlock()
+
+		for _, line := range lines {
+			w.onLine(line)
+		}
+	}
+}
+
+// PrefixWriter writes prefix at the start of every line written to W.
+type PrefixWriter struct {
+	W      io.Writer
+	prefix []byte
+	mu     sync.Mutex
+	midway bool
+}
+
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{W: w, prefix: []byte(prefix)}
+}
+
+func (w *PrefixWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := make([]byte, 0, len(p)+len(w.prefix))
+	midway := w.midway
+
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+
+		if !midway {
+			out = append(out, w.prefix...)
+		}
+
+		out = append(out, line...)
+		midway = line[len(line)-1] != '\n'
+	}
+
+	if _, err := w.W.Write(out); err != nil {
+		return 0, err
+	}
+
+	// Only a successful write moves on, so a retry gets the same prefixes.
+	w.midway = midway
+
+	return len(p), nil
+}
+
+// MultiWriteCloser writes to every sink, even when some fail, and closes
+// every sink implementing io.Closer. The errors of the sinks are joined.
+type MultiWriteCloser struct {
+	writers []io.Writer
+}
+
+func NewMultiWriteCloser(writers ...io.Writer) *MultiWriteCloser {
+	return &MultiWriteCloser{writers: writers}
+}
+
+func (m *MultiWriteCloser) Write(p []byte) (n int, err error) {
+	errs := []error{}
+
+	for _, writer := range m.writers {
+		written, err := writer.Write(p)
+
+		if err == nil && written < len(p) {
+			err = io.ErrShortWrite
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+
+	return len(p), nil
+}
+
+func (m *MultiWriteCloser) Close() error {
+	errs := []error{}
+
+	for _, writer := range m.writers {
+		if closer, ok := writer.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CountingWriter counts the bytes written to W. A nil W only counts.
+type CountingWriter struct {
+	W     io.Writer
+	count atomic.Int64
+}
+
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	return &CountingWriter{W: w}
+}
+
+func (w *CountingWriter) Write(p []byte) (n int, err error) {
+	n = len(p)
+
+	if w.W != nil {
+		n, err = w.W.Write(p)
+	}
+
+	w.count.Add(int64(n))
+
+	return n, err
+}
+
+func (w *CountingWriter) Count() int64 {
+	return w.count.Load()
+}
+
+// Close closes W when it implements io.Closer.
+func (w *CountingWriter) Close() error {
+	if closer, ok := w.W.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
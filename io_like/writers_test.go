@@ -0,0 +1,195 @@
+package io_like_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/Nuanu-com/go-utils/io_like"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type failingWriteCloser struct {
+	writeErr error
+	closeErr error
+	closed   bool
+}
+
+func (w *failingWriteCloser) Write(p []byte) (int, error) {
+	if w.writeErr != nil {
+		return 0, w.writeErr
+	}
+
+	return len(p), nil
+}
+
+func (w *failingWriteCloser) Close() error {
+	w.closed = true
+	return w.closeErr
+}
+
+var _ = Describe("Writers", func() {
+	Describe("LineWriter", func() {
+		It("emits complete lines across partial writes", func() {
+			lines := []string{}
+			writer := io_like.NewLineWriter(func(line string) { lines = append(lines, line) })
+
+			for _, chunk := range []string{"first li", "ne\r\nsecond\n", "\nthi", "rd"} {
+				n, err := io.WriteString(writer, chunk)
+				Expect(n, err).To(Equal(len(chunk)))
+			}
+
+			Expect(lines).To(Equal([]string{"first line", "second", ""}))
+
+			Expect(writer.Close()).To(Succeed())
+			Expect(lines).To(Equal([]string{"first line", "second", "", "third"}))
+		})
+
+		It("lets the callback write back to the writer", func() {
+			lines := []string{}
+
+			var writer *io_like.LineWriter
+			writer = io_like.NewLineWriter(func(line string) {
+				lines = append(lines, line)
+
+				if line == "retry" {
+					io.WriteString(writer, "retried\n")
+				}
+			})
+
+			io.WriteString(writer, "retry\n")
+			io.WriteString(writer, "done\n")
+			Expect(lines).To(Equal([]string{"retry", "retried", "done"}))
+		})
+
+		It("delivers concurrent writes in order, one line at a time", func() {
+			var (
+				mu           sync.Mutex
+				lines        []string
+				active, most int
+			)
+
+			entered := make(chan struct{})
+			release := make(chan struct{})
+			writer := io_like.NewLineWriter(func(line string) {
+				mu.Lock()
+				active++
+				most = max(most, active)
+				lines = append(lines, line)
+				mu.Unlock()
+
+				if line == "first" {
+					close(entered)
+					<-release
+				}
+
+				mu.Lock()
+				active--
+				mu.Unlock()
+			})
+
+			done := make(chan struct{})
+
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+
+				io.WriteString(writer, "first\n")
+			}()
+
+			Eventually(entered).Should(BeClosed())
+			io.WriteString(writer, "second\n")
+			close(release)
+			Eventually(done).Should(BeClosed())
+
+			Expect(lines).To(Equal([]string{"first", "second"}))
+			Expect(most).To(Equal(1))
+		})
+	})
+
+	Describe("PrefixWriter", func() {
+		It("prefixes every line", func() {
+			out := &bytes.Buffer{}
+			writer := io_like.NewPrefixWriter(out, "[job] ")
+
+			io.WriteString(writer, "one\ntw")
+			io.WriteString(writer, "o\n\nthree")
+
+			Expect(out.String()).To(Equal("[job] one\n[job] two\n[job] \n[job] three"))
+		})
+
+		It("keeps its line state when the write fails", func() {
+			sink := &failingWriteCloser{}
+			writer := io_like.NewPrefixWriter(sink, "> ")
+
+			sink.writeErr = errors.New("disk full")
+			_, err := io.WriteString(writer, "partial")
+			Expect(err).To(MatchError("disk full"))
+
+			out := &bytes.Buffer{}
+			writer.W = out
+			io.WriteString(writer, "line\n")
+
+			Expect(out.String()).To(Equal("> line\n"))
+		})
+	})
+
+	Describe("MultiWriteCloser", func() {
+		It("writes to and closes every sink, joining the errors", func() {
+			catcher := io_like.NewByteCatcher()
+			broken := &failingWriteCloser{writeErr: errors.New("disk full"), closeErr: errors.New("close failed")}
+			writer := io_like.NewMultiWriteCloser(broken, catcher)
+
+			_, err := io.WriteString(writer, "payload")
+			Expect(err).To(MatchError(ContainSubstring("disk full")))
+			Expect(catcher.String()).To(Equal("payload"))
+
+			err = writer.Close()
+			Expect(err).To(MatchError(ContainSubstring("close failed")))
+			Expect(broken.closed).To(BeTrue())
+
+			_, err = catcher.Write([]byte("late"))
+			Expect(err).To(MatchError(io.ErrClosedPipe))
+		})
+	})
+
+	Describe("CountingWriter", func() {
+		It("counts the written bytes", func() {
+			catcher := io_like.NewByteCatcher()
+			writer := io_like.NewCountingWriter(catcher)
+
+			io.WriteString(writer, "hello ")
+			io.WriteString(writer, "world")
+
+			Expect(writer.Count()).To(BeEquivalentTo(11))
+			Expect(writer.Close()).To(Succeed())
+			Expect(catcher.WriteByte('!')).To(MatchError(io.ErrClosedPipe))
+
+			discard := io_like.NewCountingWriter(nil)
+			io.WriteString(discard, "abc")
+			Expect(discard.Count()).To(BeEquivalentTo(3))
+		})
+	})
+
+	It("tees command output into a catcher and a line logger", func() {
+		logged := []string{}
+		catcher := io_like.NewBoundedByteCatcher(1024, io_like.OverflowKeepTail)
+		counter := io_like.NewCountingWriter(catcher)
+		output := io_like.NewMultiWriteCloser(
+			counter,
+			io_like.NewLineWriter(func(line string) { logged = append(logged, line) }),
+		)
+
+		_, err := io.Copy(output, strings.NewReader("build ok\r\ntests ok\ndone"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.Close()).To(Succeed())
+
+		Expect(logged).To(Equal([]string{"build ok", "tests ok", "done"}))
+		Expect(catcher.String()).To(Equal("build ok\r\ntests ok\ndone"))
+		Expect(counter.Count()).To(BeEquivalentTo(catcher.Len()))
+	})
+})
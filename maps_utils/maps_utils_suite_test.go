@@ -0,0 +1,13 @@
+package maps_utils_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMapsUtils(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MapsUtils Suite")
+}
@@ -0,0 +1,213 @@
+package maps_utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+
+	"github.com/Nuanu-com/go-utils/internal/text_utils"
+)
+
+// OrderedMap is a map that remembers insertion order. Overwriting a key keeps
+// its original position; Delete is O(n).
+type OrderedMap[K comparable, V any] struct {
+	keys   []K
+	values map[K]V
+}
+
+func NewOrderedMap[K comparable, V any](entries ...Entry[K, V]) *OrderedMap[K, V] {
+	m := &OrderedMap[K, V]{values: map[K]V{}}
+
+	for _, entry := range entries {
+		m.Set(entry.Key, entry.Value)
+	}
+
+	return m
+}
+
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if m.values == nil {
+		m.values = map[K]V{}
+	}
+
+	if _, found := m.values[key]; !found {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	v, found := m.values[key]
+
+	return v, found
+}
+
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, found := m.values[key]
+
+	return found
+}
+
+func (m *OrderedMap[K, V]) Delete(key K) {
+	if _, found := m.values[key]; !found {
+		return
+	}
+
+	delete(m.values, key)
+	m.keys = slices.DeleteFunc(m.keys, func(k K) bool { return k == key })
+}
+
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.keys)
+}
+
+func (m *OrderedMap[K, V]) Keys() []K {
+	return slices.Clone(m.keys)
+}
+
+func (m *OrderedMap[K, V]) Values() []V {
+	results := make([]V, 0, len(m.keys))
+
+	for _, k := range m.keys {
+		results = append(results, m.values[k])
+	}
+
+	return results
+}
+
+func (m *OrderedMap[K, V]) Entries() []Entry[K, V] {
+	results := make([]Entry[K, V], 0, len(m.keys))
+
+	for _, k := range m.keys {
+		results = append(results, Entry[K, V]{Key: k, Value: m.values[k]})
+	}
+
+	return results
+}
+
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, k := range m.keys {
+			if !yield(k, m.values[k]) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler, writing the keys in insertion order.
+func (m OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for idx, k := range m.keys {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+
+		keyText, err := keyToText(k)
+
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := json.Marshal(keyText)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(m.values[k])
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the document key order.
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("cannot unmarshal %v into OrderedMap", token)
+	}
+
+	result := NewOrderedMap[K, V]()
+
+	for decoder.More() {
+		token, err := decoder.Token()
+
+		if err != nil {
+			return err
+		}
+
+		key, err := textToKey[K](token.(string))
+
+		if err != nil {
+			return err
+		}
+
+		var value V
+
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		result.Set(key, value)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	*m = *result
+
+	return nil
+}
+
+func keyToText[K comparable](key K) (string, error) {
+	text, err := text_utils.EncodeText(reflect.ValueOf(key))
+
+	if errors.Is(err, text_utils.ErrUnsupportedType) {
+		return "", fmt.Errorf("unsupported OrderedMap key type %T", key)
+	}
+
+	return text, err
+}
+
+func textToKey[K comparable](text string) (K, error) {
+	var result K
+
+	err := text_utils.DecodeText(reflect.ValueOf(&result).Elem(), text)
+
+	if errors.Is(err, text_utils.ErrUnsupportedType) {
+		return result, fmt.Errorf("unsupported OrderedMap key type %T", result)
+	}
+
+	return result, err
+}
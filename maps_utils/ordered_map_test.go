@@ -0,0 +1,86 @@
+package maps_utils_test
+
+import (
+	"encoding/json"
+
+	"github.com/Nuanu-com/go-utils/maps_utils"
+	"github.com/Nuanu-com/go-utils/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OrderedMap", func() {
+	It("keeps the insertion order", func() {
+		m := maps_utils.NewOrderedMap[string, int]()
+		m.Set("z", 1)
+		m.Set("a", 2)
+		m.Set("m", 3)
+		m.Set("z", 4)
+
+		Expect(m.Keys()).To(Equal([]string{"z", "a", "m"}))
+		Expect(m.Values()).To(Equal([]int{4, 2, 3}))
+
+		m.Delete("a")
+		Expect(m.Keys()).To(Equal([]string{"z", "m"}))
+		Expect(m.Has("a")).To(BeFalse())
+		Expect(m.Len()).To(Equal(2))
+
+		v, found := m.Get("m")
+		Expect(found).To(BeTrue())
+		Expect(v).To(Equal(3))
+	})
+
+	It("marshals JSON in insertion order", func() {
+		m := maps_utils.NewOrderedMap(
+			maps_utils.Entry[string, int]{Key: "z", Value: 1},
+			maps_utils.Entry[string, int]{Key: "a", Value: 2},
+		)
+
+		data, err := json.Marshal(m)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"z":1,"a":2}`))
+	})
+
+	It("unmarshals JSON keeping the document order", func() {
+		var m maps_utils.OrderedMap[string, []int]
+
+		Expect(json.Unmarshal([]byte(`{"z":[1],"a":[2,3],"m":[]}`), &m)).To(Succeed())
+		Expect(m.Keys()).To(Equal([]string{"z", "a", "m"}))
+
+		data, err := json.Marshal(struct {
+			M maps_utils.OrderedMap[string, []int]
+		}{m})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"M":{"z":[1],"a":[2,3],"m":[]}}`))
+	})
+
+	It("supports non string keys", func() {
+		var m maps_utils.OrderedMap[types.Date, int]
+
+		Expect(json.Unmarshal([]byte(`{"2025-07-02":1,"2025-07-01":2}`), &m)).To(Succeed())
+		Expect(m.Keys()).To(Equal([]types.Date{types.MustParseDate("2025-07-02"), types.MustParseDate("2025-07-01")}))
+
+		var numbers maps_utils.OrderedMap[int, string]
+		Expect(json.Unmarshal([]byte(`{"10":"a","2":"b"}`), &numbers)).To(Succeed())
+		Expect(numbers.Keys()).To(Equal([]int{10, 2}))
+	})
+
+	It("round trips bool and float keys", func() {
+		rates := maps_utils.NewOrderedMap[float64, bool]()
+		rates.Set(0.5, true)
+		rates.Set(2, false)
+
+		data, err := json.Marshal(rates)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"0.5":true,"2":false}`))
+
+		var flags maps_utils.OrderedMap[bool, float64]
+		Expect(json.Unmarshal([]byte(`{"true":0.5,"false":2}`), &flags)).To(Succeed())
+		Expect(flags.Keys()).To(Equal([]bool{true, false}))
+
+		data, err = json.Marshal(&flags)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"true":0.5,"false":2}`))
+	})
+})
@@ -0,0 +1,152 @@
+package maps_utils
+
+import "slices"
+
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Keys returns the keys sorted with compare, or in map order when compare is
+// nil.
+func Keys[K comparable, V any](m map[K]V, compare func(a, b K) int) []K {
+	results := make([]K, 0, len(m))
+
+	for k := range m {
+		results = append(results, k)
+	}
+
+	if compare != nil {
+		slices.SortFunc(results, compare)
+	}
+
+	return results
+}
+
+// Values returns the values sorted with compare, or in map order when compare
+// is nil.
+func Values[K comparable, V any](m map[K]V, compare func(a, b V) int) []V {
+	results := make([]V, 0, len(m))
+
+	for _, v := range m {
+		results = append(results, v)
+	}
+
+	if compare != nil {
+		slices.SortFunc(results, compare)
+	}
+
+	return results
+}
+
+func MapValues[K comparable, V any, R any](m map[K]V, modifier func(K, V) R) map[K]R {
+	result := make(map[K]R, len(m))
+
+	for k, v := range m {
+		result[k] = modifier(k, v)
+	}
+
+	return result
+}
+
+// MapKeys re-keys the map. When two keys collide, which value survives is
+// unspecified.
+func MapKeys[K comparable, V any, R comparable](m map[K]V, modifier func(K, V) R) map[R]V {
+	result := make(map[R]V, len(m))
+
+	for k, v := range m {
+		result[modifier(k, v)] = v
+	}
+
+	return result
+}
+
+func FilterMap[K comparable, V any](m map[K]V, filterFn func(K, V) bool) map[K]V {
+	result := map[K]V{}
+
+	for k, v := range m {
+		if filterFn(k, v) {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+func Invert[K comparable, V comparable](m map[K]V) map[V]K {
+	result := make(map[V]K, len(m))
+
+	for k, v := range m {
+		result[v] = k
+	}
+
+	return result
+}
+
+// Merge combines the maps from left to right. When a key exists in both the
+// accumulated result and the next map, resolve picks the value to keep; a nil
+// resolve keeps the later value.
+func Merge[K comparable, V any](resolve func(key K, current V, next V) V, maps ...map[K]V) map[K]V {
+	result := map[K]V{}
+
+	for _, m := range maps {
+		for k, v := range m {
+			if current, found := result[k]; found && resolve != nil {
+				v = resolve(k, current, v)
+			}
+
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+func Pick[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	result := map[K]V{}
+
+	for _, k := range keys {
+		if v, found := m[k]; found {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+func Omit[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	result := make(map[K]V, len(m))
+
+	for k, v := range m {
+		result[k] = v
+	}
+
+	for _, k := range keys {
+		delete(result, k)
+	}
+
+	return result
+}
+
+// ToEntries returns the entries sorted by key with compare, or in map order
+// when compare is nil.
+func ToEntries[K comparable, V any](m map[K]V, compare func(a, b K) int) []Entry[K, V] {
+	results := make([]Entry[K, V], 0, len(m))
+
+	for _, k := range Keys(m, compare) {
+		results = append(results, Entry[K, V]{Key: k, Value: m[k]})
+	}
+
+	return results
+}
+
+// FromEntries builds a map from the entries; later entries win.
+func FromEntries[K comparable, V any](entries []Entry[K, V]) map[K]V {
+	result := make(map[K]V, len(entries))
+
+	for _, entry := range entries {
+		result[entry.Key] = entry.Value
+	}
+
+	return result
+}
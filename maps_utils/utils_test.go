@@ -0,0 +1,72 @@
+package maps_utils_test
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/Nuanu-com/go-utils/maps_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Maps Utils", func() {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	Context("Keys() and Values()", func() {
+		It("returns the sorted keys and values", func() {
+			Expect(maps_utils.Keys(m, strings.Compare)).To(Equal([]string{"a", "b", "c"}))
+			Expect(maps_utils.Values(m, func(a, b int) int { return cmp.Compare(b, a) })).To(Equal([]int{3, 2, 1}))
+			Expect(maps_utils.Keys(m, nil)).To(ConsistOf("a", "b", "c"))
+		})
+	})
+
+	Context("MapValues() and MapKeys()", func() {
+		It("transforms the map", func() {
+			Expect(maps_utils.MapValues(m, func(k string, v int) string { return k + k })).
+				To(Equal(map[string]string{"a": "aa", "b": "bb", "c": "cc"}))
+			Expect(maps_utils.MapKeys(m, func(k string, v int) string { return strings.ToUpper(k) })).
+				To(Equal(map[string]int{"A": 1, "B": 2, "C": 3}))
+		})
+	})
+
+	Context("FilterMap()", func() {
+		It("filters the entries", func() {
+			Expect(maps_utils.FilterMap(m, func(k string, v int) bool { return v > 1 })).To(Equal(map[string]int{"b": 2, "c": 3}))
+		})
+	})
+
+	Context("Invert()", func() {
+		It("swaps keys and values", func() {
+			Expect(maps_utils.Invert(m)).To(Equal(map[int]string{1: "a", 2: "b", 3: "c"}))
+		})
+	})
+
+	Context("Merge()", func() {
+		It("resolves the conflicts", func() {
+			sum := func(_ string, current int, next int) int { return current + next }
+
+			Expect(maps_utils.Merge(sum, m, map[string]int{"a": 10, "d": 4})).
+				To(Equal(map[string]int{"a": 11, "b": 2, "c": 3, "d": 4}))
+			Expect(maps_utils.Merge(nil, m, map[string]int{"a": 10})).
+				To(Equal(map[string]int{"a": 10, "b": 2, "c": 3}))
+		})
+	})
+
+	Context("Pick() and Omit()", func() {
+		It("selects the keys", func() {
+			Expect(maps_utils.Pick(m, "a", "z")).To(Equal(map[string]int{"a": 1}))
+			Expect(maps_utils.Omit(m, "a", "z")).To(Equal(map[string]int{"b": 2, "c": 3}))
+			Expect(m).To(HaveLen(3))
+		})
+	})
+
+	Context("ToEntries() and FromEntries()", func() {
+		It("round trips the map", func() {
+			entries := maps_utils.ToEntries(m, strings.Compare)
+
+			Expect(entries).To(Equal([]maps_utils.Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}))
+			Expect(maps_utils.FromEntries(entries)).To(Equal(m))
+		})
+	})
+})
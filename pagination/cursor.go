@@ -0,0 +1,160 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/internal/text_utils"
+	"github.com/Nuanu-com/go-utils/types"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorCodec encodes keyset sort keys into opaque tokens signed with
+// HMAC-SHA256, so clients cannot forge or tamper with them.
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec fails on an empty secret, which would make the cursors
+// forgeable.
+func NewCursorCodec(secret []byte) (*CursorCodec, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("cursor secret must not be empty")
+	}
+
+	return &CursorCodec{secret: secret}, nil
+}
+
+func (c *CursorCodec) Encode(values ...any) (string, error) {
+	parts := make([]string, len(values))
+
+	for idx, value := range values {
+		text, err := cursorText(value)
+
+		if err != nil {
+			return "", err
+		}
+
+		parts[idx] = text
+	}
+
+	payload, err := json.Marshal(parts)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies the token and fills targets, which must be pointers to
+// the types passed to Encode, in the same order.
+func (c *CursorCodec) Decode(token string, targets ...any) error {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+
+	if !found {
+		return ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return ErrInvalidCursor
+	}
+
+	var parts []string
+
+	if err := json.Unmarshal(payload, &parts); err != nil {
+		return ErrInvalidCursor
+	}
+
+	if len(parts) != len(targets) {
+		return fmt.Errorf("%w: expected %d values, got %d", ErrInvalidCursor, len(targets), len(parts))
+	}
+
+	for idx, target := range targets {
+		if err := parseCursorText(parts[idx], target); err != nil {
+			return fmt.Errorf("%w: value %d: %s", ErrInvalidCursor, idx, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
+
+func cursorText(value any) (string, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case types.LocalTime:
+		return time.Time(v).Format(time.RFC3339Nano), nil
+	case types.Date:
+		return v.Format(types.StandardDateFormat), nil
+	case uuid.UUID:
+		return v.String(), nil
+	}
+
+	text, err := text_utils.EncodeText(reflect.ValueOf(value))
+
+	if errors.Is(err, text_utils.ErrUnsupportedType) {
+		return "", fmt.Errorf("unsupported cursor value type %T", value)
+	}
+
+	return text, err
+}
+
+func parseCursorText(text string, target any) error {
+	switch t := target.(type) {
+	case *time.Time:
+		v, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return err
+		}
+		*t = v
+		return nil
+	case *types.LocalTime:
+		v, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return err
+		}
+		*t = types.LocalTime(v.Local())
+		return nil
+	}
+
+	value := reflect.ValueOf(target)
+
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("unsupported cursor target type %T", target)
+	}
+
+	err := text_utils.DecodeText(value.Elem(), text)
+
+	if errors.Is(err, text_utils.ErrUnsupportedType) {
+		return fmt.Errorf("unsupported cursor target type %T", target)
+	}
+
+	return err
+}
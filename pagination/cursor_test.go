@@ -0,0 +1,84 @@
+package pagination_test
+
+import (
+	"time"
+
+	"github.com/Nuanu-com/go-utils/pagination"
+	"github.com/Nuanu-com/go-utils/types"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CursorCodec", func() {
+	codec, _ := pagination.NewCursorCodec([]byte("secret"))
+	date := types.MustParseDate("2025-07-01")
+	at := types.LocalTime(time.Date(2025, 7, 1, 8, 30, 15, 123000000, time.Local))
+	id := uuid.MustParse("6f1c1f2e-8a4e-4c53-9d4b-0c7d1c6f2a11")
+
+	It("round trips the sort keys", func() {
+		token, err := codec.Encode(date, at, id, 42, "bca")
+		Expect(err).NotTo(HaveOccurred())
+
+		var (
+			decodedDate types.Date
+			decodedAt   types.LocalTime
+			decodedID   uuid.UUID
+			decodedInt  int
+			decodedStr  string
+		)
+
+		Expect(codec.Decode(token, &decodedDate, &decodedAt, &decodedID, &decodedInt, &decodedStr)).To(Succeed())
+		Expect(decodedDate).To(Equal(date))
+		Expect(decodedAt.Time().Equal(at.Time())).To(BeTrue())
+		Expect(decodedID).To(Equal(id))
+		Expect(decodedInt).To(Equal(42))
+		Expect(decodedStr).To(Equal("bca"))
+	})
+
+	It("round trips bool, unsigned and float keys", func() {
+		token, err := codec.Encode(true, uint16(7), 0.25)
+		Expect(err).NotTo(HaveOccurred())
+
+		var (
+			decodedBool  bool
+			decodedUint  uint16
+			decodedFloat float64
+		)
+
+		Expect(codec.Decode(token, &decodedBool, &decodedUint, &decodedFloat)).To(Succeed())
+		Expect(decodedBool).To(BeTrue())
+		Expect(decodedUint).To(Equal(uint16(7)))
+		Expect(decodedFloat).To(Equal(0.25))
+	})
+
+	It("rejects tampered or foreign tokens", func() {
+		token, err := codec.Encode(42)
+		Expect(err).NotTo(HaveOccurred())
+
+		var value int
+		other, err := pagination.NewCursorCodec([]byte("other"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other.Decode(token, &value)).To(MatchError(pagination.ErrInvalidCursor))
+		Expect(codec.Decode("x"+token, &value)).To(MatchError(pagination.ErrInvalidCursor))
+		Expect(codec.Decode("garbage", &value)).To(MatchError(pagination.ErrInvalidCursor))
+	})
+
+	It("rejects a mismatching number of targets", func() {
+		token, _ := codec.Encode(42)
+
+		var a, b int
+		Expect(codec.Decode(token, &a, &b)).To(MatchError(pagination.ErrInvalidCursor))
+	})
+
+	It("requires a secret", func() {
+		_, err := pagination.NewCursorCodec(nil)
+		Expect(err).To(MatchError("cursor secret must not be empty"))
+	})
+
+	It("rejects unsupported values", func() {
+		_, err := codec.Encode(struct{}{})
+		Expect(err).To(HaveOccurred())
+	})
+})
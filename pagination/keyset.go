@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/internal/sql_utils"
+	"github.com/Nuanu-com/go-utils/types"
+)
+
+type SortKey struct {
+	// Column is interpolated into the SQL, so it must be a trusted identifier,
+	// optionally qualified like "b.created_at", never user input.
+	Column string
+	Desc   bool
+}
+
+// Placeholder renders the n-th (1-based) bind parameter of a query.
+type Placeholder func(n int) string
+
+var DollarPlaceholder Placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+var QuestionPlaceholder Placeholder = func(int) string { return "?" }
+
+// KeysetWhere builds the condition selecting the rows after the cursor
+// values, honouring the direction of every key:
+//
+//	(a > $1) OR (a = $1 AND b < $2)
+//
+// The returned args line up with the placeholders, starting at offset+1, so
+// the clause can be appended to a query that already has offset parameters.
+// Columns that are not plain identifiers are rejected.
+func KeysetWhere(keys []SortKey, values []any, placeholder Placeholder, offset int) (string, []any, error) {
+	if len(keys) == 0 || len(keys) != len(values) {
+		return "", nil, fmt.Errorf("expected %d cursor values, got %d", len(keys), len(values))
+	}
+
+	if err := validateSortKeys(keys); err != nil {
+		return "", nil, err
+	}
+
+	clauses := []string{}
+	args := []any{}
+
+	for idx, key := range keys {
+		conditions := []string{}
+
+		for prev := range idx {
+			args = append(args, sqlValue(values[prev]))
+			conditions = append(conditions, fmt.Sprintf("%s = %s", keys[prev].Column, placeholder(offset+len(args))))
+		}
+
+		operator := ">"
+
+		if key.Desc {
+			operator = "<"
+		}
+
+		args = append(args, sqlValue(values[idx]))
+		conditions = append(conditions, fmt.Sprintf("%s %s %s", key.Column, operator, placeholder(offset+len(args))))
+		clauses = append(clauses, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	return "(" + strings.Join(clauses, " OR ") + ")", args, nil
+}
+
+// KeysetOrderBy builds the ORDER BY list matching KeysetWhere. Like
+// KeysetWhere it rejects columns that are not plain identifiers.
+func KeysetOrderBy(keys []SortKey) (string, error) {
+	if err := validateSortKeys(keys); err != nil {
+		return "", err
+	}
+
+	columns := make([]string, len(keys))
+
+	for idx, key := range keys {
+		direction := "ASC"
+
+		if key.Desc {
+			direction = "DESC"
+		}
+
+		columns[idx] = key.Column + " " + direction
+	}
+
+	return strings.Join(columns, ", "), nil
+}
+
+func validateSortKeys(keys []SortKey) error {
+	for _, key := range keys {
+		if !sql_utils.IsIdentifier(key.Column) {
+			return fmt.Errorf("invalid sort column %q", key.Column)
+		}
+	}
+
+	return nil
+}
+
+// sqlValue converts the types that do not implement driver.Valuer.
+func sqlValue(value any) any {
+	if lt, ok := value.(types.LocalTime); ok {
+		return time.Time(lt)
+	}
+
+	return value
+}
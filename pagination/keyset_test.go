@@ -0,0 +1,60 @@
+package pagination_test
+
+import (
+	"time"
+
+	"github.com/Nuanu-com/go-utils/pagination"
+	"github.com/Nuanu-com/go-utils/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KeysetWhere", func() {
+	keys := []pagination.SortKey{
+		{Column: "created_at", Desc: true},
+		{Column: "id"},
+	}
+
+	It("builds the condition with dollar placeholders", func() {
+		at := types.LocalTime(time.Date(2025, 7, 1, 8, 0, 0, 0, time.Local))
+
+		where, args, err := pagination.KeysetWhere(keys, []any{at, 10}, pagination.DollarPlaceholder, 1)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(where).To(Equal("((created_at < $2) OR (created_at = $3 AND id > $4))"))
+		Expect(args).To(Equal([]any{at.Time(), at.Time(), 10}))
+	})
+
+	It("builds the condition with question placeholders", func() {
+		where, args, err := pagination.KeysetWhere(keys[1:], []any{10}, pagination.QuestionPlaceholder, 0)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(where).To(Equal("((id > ?))"))
+		Expect(args).To(Equal([]any{10}))
+	})
+
+	It("fails when the values do not match the keys", func() {
+		_, _, err := pagination.KeysetWhere(keys, []any{10}, pagination.DollarPlaceholder, 0)
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects columns that are not identifiers", func() {
+		_, _, err := pagination.KeysetWhere([]pagination.SortKey{{Column: "b.created_at"}}, []any{1}, pagination.DollarPlaceholder, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, err = pagination.KeysetWhere([]pagination.SortKey{{Column: "id; DROP TABLE bookings"}}, []any{1}, pagination.DollarPlaceholder, 0)
+		Expect(err).To(MatchError(`invalid sort column "id; DROP TABLE bookings"`))
+
+		_, err = pagination.KeysetOrderBy([]pagination.SortKey{{Column: "id; DROP TABLE bookings"}})
+		Expect(err).To(MatchError(`invalid sort column "id; DROP TABLE bookings"`))
+	})
+
+	It("builds the matching ORDER BY", func() {
+		orderBy, err := pagination.KeysetOrderBy(keys)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orderBy).To(Equal("created_at DESC, id ASC"))
+	})
+})
@@ -0,0 +1,48 @@
+package pagination
+
+type PageMeta struct {
+	Page       int  `json:"page"`
+	Size       int  `json:"size"`
+	TotalItems int  `json:"total_items"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+}
+
+type Page[T any] struct {
+	Items []T      `json:"items"`
+	Meta  PageMeta `json:"meta"`
+}
+
+// NewPageMeta computes the metadata of a 1-based page. A page below 1 is
+// treated as the first page and a size below 1 as a single item page.
+func NewPageMeta(page int, size int, totalItems int) PageMeta {
+	page = max(page, 1)
+	size = max(size, 1)
+	totalPages := (totalItems + size - 1) / size
+
+	return PageMeta{
+		Page:       page,
+		Size:       size,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// Offset returns the SQL OFFSET of the page.
+func (m PageMeta) Offset() int {
+	return (m.Page - 1) * m.Size
+}
+
+func Paginate[T any](slice []T, page int, size int) Page[T] {
+	meta := NewPageMeta(page, size, len(slice))
+	start := min(meta.Offset(), len(slice))
+	end := min(start+meta.Size, len(slice))
+
+	items := make([]T, end-start)
+	copy(items, slice[start:end])
+
+	return Page[T]{Items: items, Meta: meta}
+}
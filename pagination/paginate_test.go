@@ -0,0 +1,48 @@
+package pagination_test
+
+import (
+	"github.com/Nuanu-com/go-utils/pagination"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Paginate", func() {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+
+	It("returns the page items and metadata", func() {
+		page := pagination.Paginate(s, 2, 3)
+
+		Expect(page.Items).To(Equal([]int{4, 5, 6}))
+		Expect(page.Meta).To(Equal(pagination.PageMeta{
+			Page:       2,
+			Size:       3,
+			TotalItems: 7,
+			TotalPages: 3,
+			HasNext:    true,
+			HasPrev:    true,
+		}))
+	})
+
+	It("returns the partial last page", func() {
+		page := pagination.Paginate(s, 3, 3)
+
+		Expect(page.Items).To(Equal([]int{7}))
+		Expect(page.Meta.HasNext).To(BeFalse())
+	})
+
+	It("returns an empty page past the end", func() {
+		page := pagination.Paginate(s, 10, 3)
+
+		Expect(page.Items).To(BeEmpty())
+		Expect(page.Meta.TotalPages).To(Equal(3))
+	})
+
+	It("clamps invalid page and size", func() {
+		page := pagination.Paginate(s, 0, 0)
+
+		Expect(page.Items).To(Equal([]int{1}))
+		Expect(page.Meta.Page).To(Equal(1))
+		Expect(page.Meta.Offset()).To(Equal(0))
+	})
+})
@@ -0,0 +1,177 @@
+package slice_utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrBatcherClosed = errors.New("batcher is closed")
+
+type BatcherOptions[T any] struct {
+	// MaxItems flushes once the buffer holds this many items. Zero disables it.
+	MaxItems int
+	// MaxBytes flushes before the summed SizeFn of the buffer would exceed
+	// it. Zero disables it.
+	MaxBytes int
+	SizeFn   func(T) int
+	// Interval flushes the buffer periodically. Zero disables it.
+	Interval time.Duration
+	Flush    func(ctx context.Context, batch []T) error
+}
+
+// Batcher accumulates items from concurrent producers and hands them to
+// Flush in batches. Batches are flushed one at a time, in the order the items
+// were added; producers block while a flush is in progress.
+type Batcher[T any] struct {
+	opts   BatcherOptions[T]
+	ctx    context.Context
+	mu     sync.Mutex
+	buffer []T
+	bytes  int
+	closed bool
+	errs   []error
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewBatcher starts the background flushing. When ctx is cancelled the
+// pending items are flushed, with a context detached from the cancellation,
+// and the batcher stops accepting items.
+func NewBatcher[T any](ctx context.Context, opts BatcherOptions[T]) *Batcher[T] {
+	b := &Batcher[T]{
+		opts: opts,
+		ctx:  context.WithoutCancel(ctx),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go b.run(ctx)
+
+	return b
+}
+
+// Add buffers the items, flushing synchronously whenever a limit is reached.
+func (b *Batcher[T]) Add(items ...T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBatcherClosed
+	}
+
+	errs := []error{}
+
+	for _, item := range items {
+		size := b.sizeOf(item)
+
+		if b.opts.MaxBytes > 0 && len(b.buffer) > 0 && b.bytes+size > b.opts.MaxBytes {
+			errs = append(errs, b.flushLocked())
+		}
+
+		b.buffer = append(b.buffer, item)
+		b.bytes += size
+
+		if b.opts.MaxItems > 0 && len(b.buffer) >= b.opts.MaxItems {
+			errs = append(errs, b.flushLocked())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *Batcher[T]) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flushLocked()
+}
+
+func (b *Batcher[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.buffer)
+}
+
+// Close stops the background flushing, flushes the pending items and returns
+// every error raised by background flushes.
+func (b *Batcher[T]) Close() error {
+	b.mu.Lock()
+
+	if !b.closed {
+		b.closed = true
+		close(b.stop)
+	}
+
+	b.mu.Unlock()
+
+	<-b.done
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err := b.flushLocked()
+	errs := b.errs
+	b.errs = nil
+
+	return errors.Join(append(errs, err)...)
+}
+
+func (b *Batcher[T]) run(ctx context.Context) {
+	defer close(b.done)
+
+	var tick <-chan time.Time
+
+	if b.opts.Interval > 0 {
+		ticker := time.NewTicker(b.opts.Interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ctx.Done():
+			b.mu.Lock()
+			b.closed = true
+			b.recordLocked(b.flushLocked())
+			b.mu.Unlock()
+
+			return
+		case <-tick:
+			b.mu.Lock()
+			b.recordLocked(b.flushLocked())
+			b.mu.Unlock()
+		}
+	}
+}
+
+func (b *Batcher[T]) flushLocked() error {
+	if len(b.buffer) == 0 {
+		return nil
+	}
+
+	batch := b.buffer
+	b.buffer = nil
+	b.bytes = 0
+
+	return b.opts.Flush(b.ctx, batch)
+}
+
+func (b *Batcher[T]) recordLocked(err error) {
+	if err != nil {
+		b.errs = append(b.errs, err)
+	}
+}
+
+func (b *Batcher[T]) sizeOf(item T) int {
+	if b.opts.SizeFn == nil {
+		return 0
+	}
+
+	return b.opts.SizeFn(item)
+}
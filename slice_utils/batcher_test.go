@@ -0,0 +1,131 @@
+package slice_utils_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batcher", Label("Utils"), func() {
+	var (
+		mu      sync.Mutex
+		batches [][]int
+	)
+
+	flush := func(_ context.Context, batch []int) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		batches = append(batches, batch)
+		return nil
+	}
+
+	flushed := func() [][]int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return batches
+	}
+
+	BeforeEach(func() {
+		batches = nil
+	})
+
+	It("flushes by count and on close", func(ctx SpecContext) {
+		batcher := slice_utils.NewBatcher(ctx, slice_utils.BatcherOptions[int]{MaxItems: 2, Flush: flush})
+
+		Expect(batcher.Add(1, 2, 3)).To(Succeed())
+		Expect(flushed()).To(Equal([][]int{{1, 2}}))
+		Expect(batcher.Len()).To(Equal(1))
+
+		Expect(batcher.Close()).To(Succeed())
+		Expect(flushed()).To(Equal([][]int{{1, 2}, {3}}))
+		Expect(batcher.Add(4)).To(MatchError(slice_utils.ErrBatcherClosed))
+	})
+
+	It("flushes by byte size", func(ctx SpecContext) {
+		batcher := slice_utils.NewBatcher(ctx, slice_utils.BatcherOptions[int]{
+			MaxBytes: 10,
+			SizeFn:   func(i int) int { return i },
+			Flush:    flush,
+		})
+
+		Expect(batcher.Add(4, 5, 3, 8)).To(Succeed())
+		Expect(batcher.Close()).To(Succeed())
+		Expect(flushed()).To(Equal([][]int{{4, 5}, {3}, {8}}))
+	})
+
+	It("flushes on the interval", func(ctx SpecContext) {
+		batcher := slice_utils.NewBatcher(ctx, slice_utils.BatcherOptions[int]{Interval: 5 * time.Millisecond, Flush: flush})
+		defer batcher.Close()
+
+		Expect(batcher.Add(1)).To(Succeed())
+		Eventually(flushed).Should(Equal([][]int{{1}}))
+	})
+
+	It("flushes cleanly when the context is cancelled", func(ctx SpecContext) {
+		parent, cancel := context.WithCancel(ctx)
+		var flushCtxErr error
+
+		batcher := slice_utils.NewBatcher(parent, slice_utils.BatcherOptions[int]{
+			Flush: func(ctx context.Context, batch []int) error {
+				flushCtxErr = ctx.Err()
+				return flush(ctx, batch)
+			},
+		})
+
+		Expect(batcher.Add(1, 2)).To(Succeed())
+		cancel()
+
+		Eventually(flushed).Should(Equal([][]int{{1, 2}}))
+		Expect(flushCtxErr).NotTo(HaveOccurred())
+		Expect(batcher.Add(3)).To(MatchError(slice_utils.ErrBatcherClosed))
+		Expect(batcher.Close()).To(Succeed())
+	})
+
+	It("is safe for concurrent producers", func(ctx SpecContext) {
+		total := 0
+		batcher := slice_utils.NewBatcher(ctx, slice_utils.BatcherOptions[int]{
+			MaxItems: 7,
+			Flush: func(_ context.Context, batch []int) error {
+				total += len(batch)
+				return nil
+			},
+		})
+
+		var wg sync.WaitGroup
+
+		for range 10 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for i := range 100 {
+					Expect(batcher.Add(i)).To(Succeed())
+				}
+			}()
+		}
+
+		wg.Wait()
+		Expect(batcher.Close()).To(Succeed())
+		Expect(total).To(Equal(1000))
+	})
+
+	It("returns the flush errors", func(ctx SpecContext) {
+		boom := errors.New("boom")
+		batcher := slice_utils.NewBatcher(ctx, slice_utils.BatcherOptions[int]{
+			MaxItems: 1,
+			Flush:    func(context.Context, []int) error { return boom },
+		})
+
+		Expect(batcher.Add(1)).To(MatchError(boom))
+		Expect(batcher.Close()).To(Succeed())
+	})
+})
@@ -0,0 +1,61 @@
+package slice_utils
+
+// Chunk splits the slice into consecutive chunks of at most size items. The
+// chunks share the backing array of slice but are capped, so appending to one
+// never overwrites the next.
+func Chunk[T any](slice []T, size int) [][]T {
+	if size <= 0 {
+		panic("slice_utils: chunk size must be greater than zero")
+	}
+
+	results := make([][]T, 0, (len(slice)+size-1)/size)
+
+	for start := 0; start < len(slice); start += size {
+		end := min(start+size, len(slice))
+		results = append(results, slice[start:end:end])
+	}
+
+	return results
+}
+
+// SlidingWindow returns every window of size consecutive items, moving one
+// item at a time. A slice shorter than size has no windows.
+func SlidingWindow[T any](slice []T, size int) [][]T {
+	if size <= 0 {
+		panic("slice_utils: window size must be greater than zero")
+	}
+
+	results := [][]T{}
+
+	for start := 0; start+size <= len(slice); start++ {
+		end := start + size
+		results = append(results, slice[start:end:end])
+	}
+
+	return results
+}
+
+// BatchBy greedily groups consecutive items so the summed sizeFn of every
+// batch stays within maxBytes. An item larger than maxBytes gets a batch of
+// its own.
+func BatchBy[T any](slice []T, sizeFn func(T) int, maxBytes int) [][]T {
+	results := [][]T{}
+	start, total := 0, 0
+
+	for idx, s := range slice {
+		size := sizeFn(s)
+
+		if idx > start && total+size > maxBytes {
+			results = append(results, slice[start:idx:idx])
+			start, total = idx, 0
+		}
+
+		total += size
+	}
+
+	if start < len(slice) {
+		results = append(results, slice[start:len(slice):len(slice)])
+	}
+
+	return results
+}
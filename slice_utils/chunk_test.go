@@ -0,0 +1,51 @@
+package slice_utils_test
+
+import (
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chunking", Label("Utils"), func() {
+	s := []int{1, 2, 3, 4, 5}
+
+	Context("Chunk()", func() {
+		It("splits the slice", func() {
+			Expect(slice_utils.Chunk(s, 2)).To(Equal([][]int{{1, 2}, {3, 4}, {5}}))
+			Expect(slice_utils.Chunk([]int{}, 2)).To(BeEmpty())
+		})
+
+		It("does not let a chunk overwrite the next one", func() {
+			chunks := slice_utils.Chunk([]int{1, 2, 3, 4}, 2)
+			_ = append(chunks[0], 99)
+
+			Expect(chunks[1]).To(Equal([]int{3, 4}))
+		})
+
+		It("panics on a non positive size", func() {
+			Expect(func() { slice_utils.Chunk(s, 0) }).To(Panic())
+		})
+	})
+
+	Context("SlidingWindow()", func() {
+		It("returns every window", func() {
+			Expect(slice_utils.SlidingWindow(s, 3)).To(Equal([][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}))
+			Expect(slice_utils.SlidingWindow(s, 6)).To(BeEmpty())
+		})
+	})
+
+	Context("BatchBy()", func() {
+		It("keeps every batch within the limit", func() {
+			rows := []string{"aaaa", "bb", "cccccc", "d", "e", "ffffffffff", "g"}
+
+			Expect(slice_utils.BatchBy(rows, func(r string) int { return len(r) }, 6)).To(Equal([][]string{
+				{"aaaa", "bb"},
+				{"cccccc"},
+				{"d", "e"},
+				{"ffffffffff"},
+				{"g"},
+			}))
+		})
+	})
+})
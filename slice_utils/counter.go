@@ -0,0 +1,78 @@
+package slice_utils
+
+import "sort"
+
+type CounterEntry[T comparable] struct {
+	Value T
+	Count int
+}
+
+// Counter is a multiset that remembers the order in which values were first
+// seen, so ties in MostCommon are stable between runs.
+type Counter[T comparable] struct {
+	counts map[T]int
+	order  []T
+}
+
+func NewCounter[T comparable](items ...T) *Counter[T] {
+	counter := &Counter[T]{counts: map[T]int{}}
+	counter.Add(items...)
+
+	return counter
+}
+
+func (c *Counter[T]) Add(items ...T) {
+	for _, item := range items {
+		c.AddN(item, 1)
+	}
+}
+
+func (c *Counter[T]) AddN(item T, n int) {
+	if _, found := c.counts[item]; !found {
+		c.order = append(c.order, item)
+	}
+
+	c.counts[item] += n
+}
+
+func (c *Counter[T]) Count(item T) int {
+	return c.counts[item]
+}
+
+func (c *Counter[T]) Len() int {
+	return len(c.order)
+}
+
+func (c *Counter[T]) Total() int {
+	total := 0
+
+	for _, count := range c.counts {
+		total += count
+	}
+
+	return total
+}
+
+func (c *Counter[T]) Entries() []CounterEntry[T] {
+	return Map(c.order, func(item T) CounterEntry[T] {
+		return CounterEntry[T]{Value: item, Count: c.counts[item]}
+	})
+}
+
+// MostCommon returns the n most frequent values. A negative n returns every
+// value.
+func (c *Counter[T]) MostCommon(n int) []CounterEntry[T] {
+	entries := c.Entries()
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Count > entries[j].Count })
+
+	if n >= 0 && n < len(entries) {
+		entries = entries[:n]
+	}
+
+	return entries
+}
+
+func (c *Counter[T]) Set() Set[T] {
+	return NewSet(c.order...)
+}
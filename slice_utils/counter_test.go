@@ -0,0 +1,30 @@
+package slice_utils_test
+
+import (
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Counter", Label("Utils"), func() {
+	It("counts the values", func() {
+		counter := slice_utils.NewCounter("a", "b", "a", "c", "b", "a")
+
+		Expect(counter.Count("a")).To(Equal(3))
+		Expect(counter.Count("z")).To(Equal(0))
+		Expect(counter.Len()).To(Equal(3))
+		Expect(counter.Total()).To(Equal(6))
+	})
+
+	It("returns the most common values with stable ties", func() {
+		counter := slice_utils.NewCounter("c", "b", "a", "b", "a")
+		counter.AddN("c", 3)
+
+		Expect(counter.MostCommon(2)).To(Equal([]slice_utils.CounterEntry[string]{
+			{Value: "c", Count: 4},
+			{Value: "b", Count: 2},
+		}))
+		Expect(counter.MostCommon(-1)).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,235 @@
+package slice_utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Pair[L any, R any] struct {
+	Left  L
+	Right R
+}
+
+type DiffResult[T any] struct {
+	// Added holds the items only present in right.
+	Added []T
+	// Removed holds the items only present in left.
+	Removed []T
+	// Changed holds the pairs sharing a key that are not equal.
+	Changed []Pair[T, T]
+	// Matched holds the pairs sharing a key that are equal.
+	Matched []Pair[T, T]
+}
+
+// DiffBy compares two slices by key in linear time. Items sharing a
+// duplicated key are paired in the order they appear.
+func DiffBy[T any, K comparable](left []T, right []T, key func(T) K, equal func(a, b T) bool) DiffResult[T] {
+	result := DiffResult[T]{
+		Added:   []T{},
+		Removed: []T{},
+		Changed: []Pair[T, T]{},
+		Matched: []Pair[T, T]{},
+	}
+
+	pending := map[K][]int{}
+
+	for idx, r := range right {
+		k := key(r)
+		pending[k] = append(pending[k], idx)
+	}
+
+	paired := make([]bool, len(right))
+
+	for _, l := range left {
+		k := key(l)
+		candidates := pending[k]
+
+		if len(candidates) == 0 {
+			result.Removed = append(result.Removed, l)
+			continue
+		}
+
+		idx := candidates[0]
+		pending[k] = candidates[1:]
+		paired[idx] = true
+
+		pair := Pair[T, T]{Left: l, Right: right[idx]}
+
+		if equal(l, right[idx]) {
+			result.Matched = append(result.Matched, pair)
+		} else {
+			result.Changed = append(result.Changed, pair)
+		}
+	}
+
+	for idx, r := range right {
+		if !paired[idx] {
+			result.Added = append(result.Added, r)
+		}
+	}
+
+	return result
+}
+
+// Tolerance is a named fuzzy matching rule used by Reconcile.
+type Tolerance[L any, R any] struct {
+	Name  string
+	Match func(L, R) bool
+}
+
+func AmountWithin[L any, R any, N Number](left func(L) N, right func(R) N, delta N) Tolerance[L, R] {
+	return Tolerance[L, R]{
+		Name: fmt.Sprintf("amount not within ±%v", delta),
+		Match: func(l L, r R) bool {
+			a, b := left(l), right(r)
+
+			if a > b {
+				return a-b <= delta
+			}
+
+			return b-a <= delta
+		},
+	}
+}
+
+// DaysWithin compares the calendar days of both times, so types.Date values
+// (d.Time) can be used directly.
+func DaysWithin[L any, R any](left func(L) time.Time, right func(R) time.Time, days int) Tolerance[L, R] {
+	return Tolerance[L, R]{
+		Name: fmt.Sprintf("date not within ±%d days", days),
+		Match: func(l L, r R) bool {
+			a, b := calendarDay(left(l)), calendarDay(right(r))
+			diff := int(a.Sub(b).Hours() / 24)
+
+			return diff >= -days && diff <= days
+		},
+	}
+}
+
+type Unmatched[T any] struct {
+	Item   T
+	Reason string
+}
+
+type ReconcileResult[L any, R any] struct {
+	Matched        []Pair[L, R]
+	UnmatchedLeft  []Unmatched[L]
+	UnmatchedRight []Unmatched[R]
+}
+
+const ReasonNoCounterpart = "no counterpart with the same key"
+const ReasonAlreadyMatched = "every counterpart with the same key was already matched"
+
+// Reconcile pairs items of two different slices. Items are bucketed by key,
+// then each left item, in order, takes the first unmatched right item of its
+// bucket passing every tolerance. The matching is greedy, so the result
+// depends on the order of left: an earlier item may take the only counterpart
+// a later one could match. Bucketing is linear but every left item scans its
+// bucket, so the time grows with the square of the bucket size; prefer keys
+// selective enough to keep the buckets small. Unmatched items carry the
+// reason of their closest miss, the candidate failing the fewest tolerances.
+func Reconcile[L any, R any, K comparable](
+	left []L,
+	right []R,
+	leftKey func(L) K,
+	rightKey func(R) K,
+	tolerances ...Tolerance[L, R],
+) ReconcileResult[L, R] {
+	result := ReconcileResult[L, R]{
+		Matched:        []Pair[L, R]{},
+		UnmatchedLeft:  []Unmatched[L]{},
+		UnmatchedRight: []Unmatched[R]{},
+	}
+
+	buckets := map[K][]int{}
+
+	for idx, r := range right {
+		k := rightKey(r)
+		buckets[k] = append(buckets[k], idx)
+	}
+
+	paired := make([]bool, len(right))
+	missed := map[int][]string{}
+	leftKeys := Set[K]{}
+
+	for _, l := range left {
+		k := leftKey(l)
+		leftKeys.Add(k)
+		candidates := buckets[k]
+
+		if len(candidates) == 0 {
+			result.UnmatchedLeft = append(result.UnmatchedLeft, Unmatched[L]{Item: l, Reason: ReasonNoCounterpart})
+			continue
+		}
+
+		var closest []string
+		matched := -1
+
+		for _, idx := range candidates {
+			if paired[idx] {
+				continue
+			}
+
+			failures := []string{}
+
+			for _, tolerance := range tolerances {
+				if !tolerance.Match(l, right[idx]) {
+					failures = append(failures, tolerance.Name)
+				}
+			}
+
+			if len(failures) == 0 {
+				matched = idx
+				break
+			}
+
+			if closest == nil || len(failures) < len(closest) {
+				closest = failures
+			}
+
+			if previous, found := missed[idx]; !found || len(failures) < len(previous) {
+				missed[idx] = failures
+			}
+		}
+
+		if matched >= 0 {
+			paired[matched] = true
+			result.Matched = append(result.Matched, Pair[L, R]{Left: l, Right: right[matched]})
+			continue
+		}
+
+		reason := ReasonAlreadyMatched
+
+		if closest != nil {
+			reason = strings.Join(closest, ", ")
+		}
+
+		result.UnmatchedLeft = append(result.UnmatchedLeft, Unmatched[L]{Item: l, Reason: reason})
+	}
+
+	for idx, r := range right {
+		if paired[idx] {
+			continue
+		}
+
+		failures, found := missed[idx]
+		reason := strings.Join(failures, ", ")
+
+		if !found {
+			reason = ReasonNoCounterpart
+
+			if leftKeys.Contains(rightKey(r)) {
+				reason = ReasonAlreadyMatched
+			}
+		}
+
+		result.UnmatchedRight = append(result.UnmatchedRight, Unmatched[R]{Item: r, Reason: reason})
+	}
+
+	return result
+}
+
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,123 @@
+package slice_utils_test
+
+import (
+	"time"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diffing", Label("Utils"), func() {
+	type row struct {
+		Ref    string
+		Amount int
+	}
+
+	Context("DiffBy()", func() {
+		It("splits the data into added, removed, changed and matched", func() {
+			left := []row{{"a", 1}, {"b", 2}, {"c", 3}, {"c", 4}}
+			right := []row{{"b", 2}, {"c", 30}, {"d", 5}, {"c", 4}}
+
+			result := slice_utils.DiffBy(left, right, func(r row) string { return r.Ref }, func(a, b row) bool { return a == b })
+
+			Expect(result.Removed).To(Equal([]row{{"a", 1}}))
+			Expect(result.Added).To(Equal([]row{{"d", 5}}))
+			Expect(result.Changed).To(Equal([]slice_utils.Pair[row, row]{{Left: row{"c", 3}, Right: row{"c", 30}}}))
+			Expect(result.Matched).To(Equal([]slice_utils.Pair[row, row]{
+				{Left: row{"b", 2}, Right: row{"b", 2}},
+				{Left: row{"c", 4}, Right: row{"c", 4}},
+			}))
+		})
+	})
+
+	Context("Reconcile()", func() {
+		type ledger struct {
+			Account string
+			Amount  int
+			Date    time.Time
+		}
+
+		type statement struct {
+			Account string
+			Amount  float64
+			Date    time.Time
+		}
+
+		day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+
+		tolerances := []slice_utils.Tolerance[ledger, statement]{
+			slice_utils.AmountWithin(func(l ledger) float64 { return float64(l.Amount) }, func(s statement) float64 { return s.Amount }, 500),
+			slice_utils.DaysWithin(func(l ledger) time.Time { return l.Date }, func(s statement) time.Time { return s.Date }, 1),
+		}
+
+		It("matches within the tolerances and reports the reasons", func() {
+			ours := []ledger{
+				{"bca", 10_000, day(1)},
+				{"bca", 20_000, day(3)},
+				{"bni", 5_000, day(1)},
+				{"mandiri", 1_000, day(1)},
+			}
+
+			bank := []statement{
+				{"bca", 20_100, day(4)},
+				{"bca", 9_800, day(2)},
+				{"bni", 5_000, day(9)},
+				{"permata", 1_000, day(1)},
+			}
+
+			result := slice_utils.Reconcile(ours, bank,
+				func(l ledger) string { return l.Account },
+				func(s statement) string { return s.Account },
+				tolerances...,
+			)
+
+			Expect(result.Matched).To(Equal([]slice_utils.Pair[ledger, statement]{
+				{Left: ours[0], Right: bank[1]},
+				{Left: ours[1], Right: bank[0]},
+			}))
+			Expect(result.UnmatchedLeft).To(Equal([]slice_utils.Unmatched[ledger]{
+				{Item: ours[2], Reason: "date not within ±1 days"},
+				{Item: ours[3], Reason: slice_utils.ReasonNoCounterpart},
+			}))
+			Expect(result.UnmatchedRight).To(Equal([]slice_utils.Unmatched[statement]{
+				{Item: bank[2], Reason: "date not within ±1 days"},
+				{Item: bank[3], Reason: slice_utils.ReasonNoCounterpart},
+			}))
+		})
+
+		It("reports counterparts that were already matched", func() {
+			ours := []ledger{{"bca", 100, day(1)}, {"bca", 100, day(1)}}
+			bank := []statement{{"bca", 100, day(1)}}
+
+			result := slice_utils.Reconcile(ours, bank,
+				func(l ledger) string { return l.Account },
+				func(s statement) string { return s.Account },
+				tolerances...,
+			)
+
+			Expect(result.Matched).To(HaveLen(1))
+			Expect(result.UnmatchedLeft).To(Equal([]slice_utils.Unmatched[ledger]{
+				{Item: ours[1], Reason: slice_utils.ReasonAlreadyMatched},
+			}))
+			Expect(result.UnmatchedRight).To(BeEmpty())
+		})
+
+		It("reports the closest miss of unmatched right items", func() {
+			ours := []ledger{{"bca", 100, day(9)}, {"bca", 5_000, day(9)}}
+			bank := []statement{{"bca", 100, day(1)}}
+
+			result := slice_utils.Reconcile(ours, bank,
+				func(l ledger) string { return l.Account },
+				func(s statement) string { return s.Account },
+				tolerances...,
+			)
+
+			Expect(result.Matched).To(BeEmpty())
+			Expect(result.UnmatchedRight).To(Equal([]slice_utils.Unmatched[statement]{
+				{Item: bank[0], Reason: "date not within ±1 days"},
+			}))
+		})
+	})
+})
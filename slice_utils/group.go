@@ -0,0 +1,163 @@
+package slice_utils
+
+import "fmt"
+
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+type Group[K comparable, T any] struct {
+	Key   K
+	Items []T
+}
+
+// Key2 and Key3 are ready made composite keys for grouping by several fields.
+type Key2[A comparable, B comparable] struct {
+	First  A
+	Second B
+}
+
+type Key3[A comparable, B comparable, C comparable] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+func NewKey2[A comparable, B comparable](first A, second B) Key2[A, B] {
+	return Key2[A, B]{First: first, Second: second}
+}
+
+func NewKey3[A comparable, B comparable, C comparable](first A, second B, third C) Key3[A, B, C] {
+	return Key3[A, B, C]{First: first, Second: second, Third: third}
+}
+
+type DuplicateKeyError[K comparable] struct {
+	Key   K
+	Index int
+}
+
+func (e *DuplicateKeyError[K]) Error() string {
+	return fmt.Sprintf("duplicate key %v at index %d", e.Key, e.Index)
+}
+
+// GroupByOrdered works like GroupBy but keeps the groups in the order their
+// keys were first seen.
+func GroupByOrdered[T any, K comparable](slice []T, key func(T) K) []Group[K, T] {
+	result := []Group[K, T]{}
+	positions := map[K]int{}
+
+	for _, s := range slice {
+		k := key(s)
+		position, found := positions[k]
+
+		if !found {
+			position = len(result)
+			positions[k] = position
+			result = append(result, Group[K, T]{Key: k})
+		}
+
+		result[position].Items = append(result[position].Items, s)
+	}
+
+	return result
+}
+
+// KeyBy indexes the slice by a unique key and fails on the first duplicate.
+func KeyBy[T any, K comparable](slice []T, key func(T) K) (map[K]T, error) {
+	result := make(map[K]T, len(slice))
+
+	for idx, s := range slice {
+		k := key(s)
+
+		if _, found := result[k]; found {
+			return nil, &DuplicateKeyError[K]{Key: k, Index: idx}
+		}
+
+		result[k] = s
+	}
+
+	return result, nil
+}
+
+func Partition[T any](slice []T, predicate func(T) bool) ([]T, []T) {
+	pass := []T{}
+	fail := []T{}
+
+	for _, s := range slice {
+		if predicate(s) {
+			pass = append(pass, s)
+		} else {
+			fail = append(fail, s)
+		}
+	}
+
+	return pass, fail
+}
+
+func CountBy[T any, K comparable](slice []T, key func(T) K) map[K]int {
+	result := map[K]int{}
+
+	for _, s := range slice {
+		result[key(s)]++
+	}
+
+	return result
+}
+
+func SumBy[T any, K comparable, N Number](slice []T, key func(T) K, value func(T) N) map[K]N {
+	result := map[K]N{}
+
+	for _, s := range slice {
+		result[key(s)] += value(s)
+	}
+
+	return result
+}
+
+type Summary[N Number] struct {
+	Count int
+	Sum   N
+	Min   N
+	Max   N
+	Avg   float64
+}
+
+type Aggregate[K comparable, N Number] struct {
+	Key K
+	Summary[N]
+}
+
+func Summarize[T any, N Number](slice []T, value func(T) N) Summary[N] {
+	result := Summary[N]{}
+
+	for idx, s := range slice {
+		v := value(s)
+
+		if idx == 0 || v < result.Min {
+			result.Min = v
+		}
+
+		if idx == 0 || v > result.Max {
+			result.Max = v
+		}
+
+		result.Sum += v
+		result.Count++
+	}
+
+	if result.Count > 0 {
+		result.Avg = float64(result.Sum) / float64(result.Count)
+	}
+
+	return result
+}
+
+// AggregateBy groups the slice in first-seen key order and summarizes every
+// group. Use Key2 or Key3 to aggregate over composite keys.
+func AggregateBy[T any, K comparable, N Number](slice []T, key func(T) K, value func(T) N) []Aggregate[K, N] {
+	return Map(GroupByOrdered(slice, key), func(group Group[K, T]) Aggregate[K, N] {
+		return Aggregate[K, N]{Key: group.Key, Summary: Summarize(group.Items, value)}
+	})
+}
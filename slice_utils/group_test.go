@@ -0,0 +1,100 @@
+package slice_utils_test
+
+import (
+	"errors"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Grouping", Label("Utils"), func() {
+	type transaction struct {
+		ID      int
+		Account string
+		Date    string
+		Amount  int
+	}
+
+	transactions := []transaction{
+		{1, "bca", "2025-07-02", 100},
+		{2, "mandiri", "2025-07-01", 50},
+		{3, "bca", "2025-07-01", 300},
+		{4, "bni", "2025-07-01", 10},
+		{5, "bca", "2025-07-02", 200},
+	}
+
+	account := func(t transaction) string { return t.Account }
+	amount := func(t transaction) int { return t.Amount }
+
+	Context("GroupByOrdered()", func() {
+		It("keeps the first seen key order", func() {
+			groups := slice_utils.GroupByOrdered(transactions, account)
+
+			Expect(slice_utils.Map(groups, func(g slice_utils.Group[string, transaction]) string { return g.Key })).
+				To(Equal([]string{"bca", "mandiri", "bni"}))
+			Expect(groups[0].Items).To(Equal([]transaction{transactions[0], transactions[2], transactions[4]}))
+		})
+	})
+
+	Context("KeyBy()", func() {
+		It("indexes the data by a unique key", func() {
+			result, err := slice_utils.KeyBy(transactions, func(t transaction) int { return t.ID })
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result[3]).To(Equal(transactions[2]))
+		})
+
+		It("fails on duplicated keys", func() {
+			_, err := slice_utils.KeyBy(transactions, account)
+
+			var duplicate *slice_utils.DuplicateKeyError[string]
+			Expect(errors.As(err, &duplicate)).To(BeTrue())
+			Expect(duplicate.Key).To(Equal("bca"))
+			Expect(duplicate.Index).To(Equal(2))
+		})
+	})
+
+	Context("Partition()", func() {
+		It("splits the data by the predicate", func() {
+			pass, fail := slice_utils.Partition([]int{1, 2, 3, 4, 5}, func(i int) bool { return i%2 == 0 })
+
+			Expect(pass).To(Equal([]int{2, 4}))
+			Expect(fail).To(Equal([]int{1, 3, 5}))
+		})
+	})
+
+	Context("CountBy() and SumBy()", func() {
+		It("counts and sums per key", func() {
+			Expect(slice_utils.CountBy(transactions, account)).To(Equal(map[string]int{"bca": 3, "mandiri": 1, "bni": 1}))
+			Expect(slice_utils.SumBy(transactions, account, amount)).To(Equal(map[string]int{"bca": 600, "mandiri": 50, "bni": 10}))
+		})
+	})
+
+	Context("AggregateBy()", func() {
+		It("summarizes every group", func() {
+			result := slice_utils.AggregateBy(transactions, account, amount)
+
+			Expect(result[0]).To(Equal(slice_utils.Aggregate[string, int]{
+				Key:     "bca",
+				Summary: slice_utils.Summary[int]{Count: 3, Sum: 600, Min: 100, Max: 300, Avg: 200},
+			}))
+		})
+
+		It("groups by composite keys", func() {
+			result := slice_utils.AggregateBy(transactions, func(t transaction) slice_utils.Key2[string, string] {
+				return slice_utils.NewKey2(t.Account, t.Date)
+			}, amount)
+
+			Expect(result).To(HaveLen(4))
+			Expect(result[0].Key).To(Equal(slice_utils.NewKey2("bca", "2025-07-02")))
+			Expect(result[0].Sum).To(Equal(300))
+			Expect(result[0].Avg).To(Equal(float64(150)))
+		})
+
+		It("returns an empty summary for no data", func() {
+			Expect(slice_utils.Summarize([]transaction{}, amount)).To(Equal(slice_utils.Summary[int]{}))
+		})
+	})
+})
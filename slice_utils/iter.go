@@ -0,0 +1,180 @@
+package slice_utils
+
+import "iter"
+
+// The *Seq functions are lazy counterparts of the eager helpers in utils.go.
+// They compose without allocating intermediate slices; use Collect to
+// materialise the final result.
+
+func Values[T any](slice []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, s := range slice {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+func Collect[T any](seq iter.Seq[T]) []T {
+	results := []T{}
+
+	for s := range seq {
+		results = append(results, s)
+	}
+
+	return results
+}
+
+func MapSeq[T any, R any](seq iter.Seq[T], modifier func(i T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for s := range seq {
+			if !yield(modifier(s)) {
+				return
+			}
+		}
+	}
+}
+
+func FilterSeq[T any](seq iter.Seq[T], filterFn func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s := range seq {
+			if filterFn(s) && !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+func TakeSeq[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+
+		taken := 0
+
+		for s := range seq {
+			if !yield(s) {
+				return
+			}
+
+			taken++
+
+			if taken >= n {
+				return
+			}
+		}
+	}
+}
+
+func SkipSeq[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		skipped := 0
+
+		for s := range seq {
+			if skipped < n {
+				skipped++
+				continue
+			}
+
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// ChunkSeq yields consecutive slices of at most size items. Every chunk is a
+// freshly allocated slice, so it is safe to retain after the next iteration.
+// Like the other sequences, it checks its arguments lazily: a size below one
+// panics when the sequence is iterated, not when it is built.
+func ChunkSeq[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size <= 0 {
+			panic("slice_utils: chunk size must be greater than zero")
+		}
+
+		chunk := make([]T, 0, size)
+
+		for s := range seq {
+			chunk = append(chunk, s)
+
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+
+				chunk = make([]T, 0, size)
+			}
+		}
+
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// ZipSeq pairs items of both sequences and stops when the shorter one ends.
+func ZipSeq[A any, B any](left iter.Seq[A], right iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		next, stop := iter.Pull(right)
+		defer stop()
+
+		for a := range left {
+			b, ok := next()
+
+			if !ok {
+				return
+			}
+
+			if !yield(a, b) {
+				return
+			}
+		}
+	}
+}
+
+func EnumerateSeq[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		idx := 0
+
+		for s := range seq {
+			if !yield(idx, s) {
+				return
+			}
+
+			idx++
+		}
+	}
+}
+
+func FlatMapSeq[T any, R any](seq iter.Seq[T], modifier func(i T) iter.Seq[R]) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for s := range seq {
+			for r := range modifier(s) {
+				if !yield(r) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func DistinctSeq[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := map[T]struct{}{}
+
+		for s := range seq {
+			if _, found := seen[s]; found {
+				continue
+			}
+
+			seen[s] = struct{}{}
+
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package slice_utils_test
+
+import (
+	"testing"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+)
+
+// benchmarkSink keeps the compiler from optimising the pipelines away.
+var benchmarkSink int
+
+var benchmarkRows = func() []int {
+	rows := make([]int, 200_000)
+
+	for i := range rows {
+		rows[i] = i
+	}
+
+	return rows
+}()
+
+func BenchmarkEagerPipeline(b *testing.B) {
+	for b.Loop() {
+		doubled := slice_utils.Map(benchmarkRows, func(i int) int { return i * 2 })
+		filtered := slice_utils.Filter(doubled, func(i int) bool { return i%3 == 0 })
+		benchmarkSink = slice_utils.Reduce(filtered, 0, func(sum int, i int) int { return sum + i })
+	}
+}
+
+func BenchmarkLazyPipeline(b *testing.B) {
+	for b.Loop() {
+		seq := slice_utils.MapSeq(slice_utils.Values(benchmarkRows), func(i int) int { return i * 2 })
+		seq = slice_utils.FilterSeq(seq, func(i int) bool { return i%3 == 0 })
+
+		sum := 0
+		for i := range seq {
+			sum += i
+		}
+
+		benchmarkSink = sum
+	}
+}
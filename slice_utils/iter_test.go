@@ -0,0 +1,99 @@
+package slice_utils_test
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lazy sequences", Label("Utils"), func() {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	Context("MapSeq() and FilterSeq()", func() {
+		It("composes without evaluating skipped items", func() {
+			calls := 0
+			seq := slice_utils.MapSeq(slice_utils.Values(s), func(i int) int {
+				calls++
+				return i * 2
+			})
+			seq = slice_utils.FilterSeq(seq, func(i int) bool { return i%4 == 0 })
+
+			Expect(slice_utils.Collect(slice_utils.TakeSeq(seq, 2))).To(Equal([]int{4, 8}))
+			Expect(calls).To(Equal(4))
+		})
+	})
+
+	Context("TakeSeq() and SkipSeq()", func() {
+		It("limits and offsets the sequence", func() {
+			Expect(slice_utils.Collect(slice_utils.TakeSeq(slice_utils.Values(s), 3))).To(Equal([]int{1, 2, 3}))
+			Expect(slice_utils.Collect(slice_utils.TakeSeq(slice_utils.Values(s), 0))).To(Equal([]int{}))
+			Expect(slice_utils.Collect(slice_utils.SkipSeq(slice_utils.Values(s), 8))).To(Equal([]int{9, 10}))
+			Expect(slice_utils.Collect(slice_utils.SkipSeq(slice_utils.Values(s), 20))).To(Equal([]int{}))
+		})
+	})
+
+	Context("ChunkSeq()", func() {
+		It("splits the sequence into chunks", func() {
+			Expect(slice_utils.Collect(slice_utils.ChunkSeq(slice_utils.Values(s), 4))).To(Equal([][]int{
+				{1, 2, 3, 4},
+				{5, 6, 7, 8},
+				{9, 10},
+			}))
+		})
+
+		It("panics on a non positive size", func() {
+			seq := slice_utils.ChunkSeq(slice_utils.Values(s), 0)
+			Expect(func() { slice_utils.Collect(seq) }).To(Panic())
+		})
+	})
+
+	Context("ZipSeq()", func() {
+		It("stops at the shorter sequence", func() {
+			keys := []string{}
+			values := []int{}
+
+			for k, v := range slice_utils.ZipSeq(slice_utils.Values([]string{"a", "b"}), slice_utils.Values(s)) {
+				keys = append(keys, k)
+				values = append(values, v)
+			}
+
+			Expect(keys).To(Equal([]string{"a", "b"}))
+			Expect(values).To(Equal([]int{1, 2}))
+		})
+	})
+
+	Context("EnumerateSeq()", func() {
+		It("yields the index of every item", func() {
+			indexes := []int{}
+
+			for idx, v := range slice_utils.EnumerateSeq(slice_utils.Values([]string{"a", "b", "c"})) {
+				indexes = append(indexes, idx)
+				Expect(v).NotTo(BeEmpty())
+			}
+
+			Expect(indexes).To(Equal([]int{0, 1, 2}))
+		})
+	})
+
+	Context("FlatMapSeq()", func() {
+		It("flattens the nested sequences", func() {
+			result := slice_utils.Collect(slice_utils.FlatMapSeq(slice_utils.Values([]int{1, 2, 3}), func(i int) iter.Seq[int] {
+				return slices.Values(slices.Repeat([]int{i}, i))
+			}))
+
+			Expect(result).To(Equal([]int{1, 2, 2, 3, 3, 3}))
+		})
+	})
+
+	Context("DistinctSeq()", func() {
+		It("keeps the first occurrence", func() {
+			result := slice_utils.Collect(slice_utils.DistinctSeq(slice_utils.Values([]int{3, 1, 3, 2, 1})))
+
+			Expect(result).To(Equal([]int{3, 1, 2}))
+		})
+	})
+})
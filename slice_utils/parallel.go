@@ -0,0 +1,179 @@
+package slice_utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+)
+
+type ParallelOptions struct {
+	// Limit is the maximum number of concurrent workers.
+	// Zero or negative uses runtime.GOMAXPROCS(0).
+	Limit int
+	// CollectErrors keeps processing after a failure and returns every
+	// error joined with errors.Join instead of cancelling on the first one.
+	CollectErrors bool
+}
+
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d: %s", e.Index, e.Err.Error())
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+func ParallelMap[T any, R any](
+	ctx context.Context,
+	slice []T,
+	opts ParallelOptions,
+	modifier func(context.Context, T) (R, error),
+) ([]R, error) {
+	results := make([]R, len(slice))
+
+	err := parallelRun(ctx, len(slice), opts, func(ctx context.Context, idx int) error {
+		res, err := modifier(ctx, slice[idx])
+
+		if err != nil {
+			return err
+		}
+
+		results[idx] = res
+		return nil
+	})
+
+	if err != nil && !opts.CollectErrors {
+		return nil, err
+	}
+
+	return results, err
+}
+
+func ParallelFilter[T any](
+	ctx context.Context,
+	slice []T,
+	opts ParallelOptions,
+	filterFn func(context.Context, T) (bool, error),
+) ([]T, error) {
+	keep, err := ParallelMap(ctx, slice, opts, filterFn)
+
+	if keep == nil {
+		return nil, err
+	}
+
+	results := []T{}
+
+	for idx, s := range slice {
+		if keep[idx] {
+			results = append(results, s)
+		}
+	}
+
+	return results, err
+}
+
+func ParallelForEach[T any](
+	ctx context.Context,
+	slice []T,
+	opts ParallelOptions,
+	fn func(context.Context, T) error,
+) error {
+	return parallelRun(ctx, len(slice), opts, func(ctx context.Context, idx int) error {
+		return fn(ctx, slice[idx])
+	})
+}
+
+func parallelRun(ctx context.Context, size int, opts ParallelOptions, fn func(context.Context, int) error) error {
+	limit := opts.Limit
+
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+
+	limit = min(limit, size)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		errs []*IndexError
+		wg   sync.WaitGroup
+	)
+
+	indexes := make(chan int)
+
+	for range limit {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for idx := range indexes {
+				if err := safeCall(ctx, idx, fn); err != nil {
+					mu.Lock()
+					errs = append(errs, &IndexError{Index: idx, Err: err})
+					mu.Unlock()
+
+					if !opts.CollectErrors {
+						cancel()
+					}
+				}
+			}
+		}()
+	}
+
+feed:
+	for idx := range size {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- idx:
+		}
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	if len(errs) == 0 {
+		// cancel() only fires on failures, so a done context here means the
+		// caller cancelled it.
+		return ctx.Err()
+	}
+
+	if !opts.CollectErrors {
+		return errs[0]
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+
+	return errors.Join(Map(errs, func(e *IndexError) error { return e })...)
+}
+
+func safeCall(ctx context.Context, idx int, fn func(context.Context, int) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn(ctx, idx)
+}
@@ -0,0 +1,134 @@
+package slice_utils_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parallel", Label("Utils"), func() {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	Context("ParallelMap()", func() {
+		It("preserves the input order", func(ctx SpecContext) {
+			result, err := slice_utils.ParallelMap(ctx, s, slice_utils.ParallelOptions{Limit: 3}, func(_ context.Context, i int) (int, error) {
+				time.Sleep(time.Duration(10-i) * time.Millisecond)
+				return i * 2, nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal([]int{2, 4, 6, 8, 10, 12, 14, 16, 18, 20}))
+		})
+
+		It("never exceeds the worker limit", func(ctx SpecContext) {
+			var running, peak atomic.Int32
+
+			_, err := slice_utils.ParallelMap(ctx, s, slice_utils.ParallelOptions{Limit: 2}, func(_ context.Context, i int) (int, error) {
+				current := running.Add(1)
+				defer running.Add(-1)
+
+				for {
+					old := peak.Load()
+					if current <= old || peak.CompareAndSwap(old, current) {
+						break
+					}
+				}
+
+				time.Sleep(2 * time.Millisecond)
+				return i, nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(peak.Load()).To(BeNumerically("<=", 2))
+		})
+
+		It("cancels the remaining work on the first error", func(ctx SpecContext) {
+			boom := errors.New("boom")
+			var calls atomic.Int32
+
+			result, err := slice_utils.ParallelMap(ctx, s, slice_utils.ParallelOptions{Limit: 1}, func(_ context.Context, i int) (int, error) {
+				calls.Add(1)
+				if i == 3 {
+					return 0, boom
+				}
+				return i, nil
+			})
+
+			Expect(result).To(BeNil())
+			Expect(err).To(MatchError(boom))
+
+			var indexErr *slice_utils.IndexError
+			Expect(errors.As(err, &indexErr)).To(BeTrue())
+			Expect(indexErr.Index).To(Equal(2))
+			Expect(calls.Load()).To(BeNumerically("<", int32(len(s))))
+		})
+
+		It("collects every error when asked to", func(ctx SpecContext) {
+			result, err := slice_utils.ParallelMap(ctx, s, slice_utils.ParallelOptions{Limit: 4, CollectErrors: true}, func(_ context.Context, i int) (int, error) {
+				if i%2 == 0 {
+					return 0, errors.New("even")
+				}
+				return i, nil
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.(interface{ Unwrap() []error }).Unwrap()).To(HaveLen(5))
+			Expect(result).To(Equal([]int{1, 0, 3, 0, 5, 0, 7, 0, 9, 0}))
+		})
+
+		It("recovers panics into errors", func(ctx SpecContext) {
+			_, err := slice_utils.ParallelMap(ctx, s, slice_utils.ParallelOptions{}, func(_ context.Context, i int) (int, error) {
+				if i == 5 {
+					panic("kaboom")
+				}
+				return i, nil
+			})
+
+			var panicErr *slice_utils.PanicError
+			Expect(errors.As(err, &panicErr)).To(BeTrue())
+			Expect(panicErr.Value).To(Equal("kaboom"))
+		})
+
+		It("stops when the parent context is cancelled", func(ctx SpecContext) {
+			parent, cancel := context.WithCancel(ctx)
+			cancel()
+
+			_, err := slice_utils.ParallelMap(parent, s, slice_utils.ParallelOptions{Limit: 1}, func(_ context.Context, i int) (int, error) {
+				return i, nil
+			})
+
+			Expect(err).To(MatchError(context.Canceled))
+		})
+	})
+
+	Context("ParallelFilter()", func() {
+		It("filters the data in order", func(ctx SpecContext) {
+			result, err := slice_utils.ParallelFilter(ctx, s, slice_utils.ParallelOptions{Limit: 3}, func(_ context.Context, i int) (bool, error) {
+				return i%2 == 0, nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal([]int{2, 4, 6, 8, 10}))
+		})
+	})
+
+	Context("ParallelForEach()", func() {
+		It("visits every item", func(ctx SpecContext) {
+			var sum atomic.Int64
+
+			err := slice_utils.ParallelForEach(ctx, s, slice_utils.ParallelOptions{Limit: 3}, func(_ context.Context, i int) error {
+				sum.Add(int64(i))
+				return nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sum.Load()).To(Equal(int64(55)))
+		})
+	})
+})
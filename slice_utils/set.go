@@ -0,0 +1,382 @@
+package slice_utils
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Nuanu-com/go-utils/internal/text_utils"
+)
+
+type Set[T comparable] map[T]struct{}
+
+func NewSet[T comparable](items ...T) Set[T] {
+	result := make(Set[T], len(items))
+
+	for _, item := range items {
+		result[item] = struct{}{}
+	}
+
+	return result
+}
+
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+}
+
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+func (s Set[T]) Contains(item T) bool {
+	_, found := s[item]
+
+	return found
+}
+
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+func (s Set[T]) Clone() Set[T] {
+	result := make(Set[T], len(s))
+
+	for item := range s {
+		result[item] = struct{}{}
+	}
+
+	return result
+}
+
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	result := s.Clone()
+
+	for item := range other {
+		result[item] = struct{}{}
+	}
+
+	return result
+}
+
+func (s Set[T]) Intersect(other Set[T]) Set[T] {
+	result := Set[T]{}
+
+	for item := range s {
+		if other.Contains(item) {
+			result[item] = struct{}{}
+		}
+	}
+
+	return result
+}
+
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	result := Set[T]{}
+
+	for item := range s {
+		if !other.Contains(item) {
+			result[item] = struct{}{}
+		}
+	}
+
+	return result
+}
+
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return s.Difference(other).Union(other.Difference(s))
+}
+
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+
+	for item := range s {
+		if !other.Contains(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s Set[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// All iterates the set in sorted order. Strings and numbers are compared
+// naturally, anything else by its text or JSON representation.
+func (s Set[T]) All() iter.Seq[T] {
+	return Values(s.Sorted())
+}
+
+func (s Set[T]) Sorted() []T {
+	return s.SortedFunc(compareAny[T])
+}
+
+func (s Set[T]) SortedFunc(compare func(a, b T) int) []T {
+	result := make([]T, 0, len(s))
+
+	for item := range s {
+		result = append(result, item)
+	}
+
+	slices.SortFunc(result, compare)
+
+	return result
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Sorted())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	*s = NewSet(items...)
+
+	return nil
+}
+
+// Value implements driver.Valuer as a PostgreSQL array literal.
+func (s Set[T]) Value() (driver.Value, error) {
+	parts := []string{}
+
+	for _, item := range s.Sorted() {
+		text, err := textOf(item)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, quoteArrayElement(text))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}", nil
+}
+
+// Scan implements sql.Scanner for PostgreSQL array literals.
+func (s *Set[T]) Scan(src any) error {
+	var literal string
+
+	switch data := src.(type) {
+	case nil:
+		*s = Set[T]{}
+		return nil
+	case []byte:
+		literal = string(data)
+	case string:
+		literal = data
+	default:
+		return fmt.Errorf("cannot scan %T into Set", src)
+	}
+
+	elements, err := parseArrayLiteral(literal)
+
+	if err != nil {
+		return err
+	}
+
+	result := make(Set[T], len(elements))
+
+	for _, element := range elements {
+		item, err := parseText[T](element)
+
+		if err != nil {
+			return err
+		}
+
+		result[item] = struct{}{}
+	}
+
+	*s = result
+
+	return nil
+}
+
+func Uniq[T comparable](slice []T) []T {
+	return UniqBy(slice, func(item T) T { return item })
+}
+
+// UniqBy keeps the first item of every key, in input order.
+func UniqBy[T any, K comparable](slice []T, key func(T) K) []T {
+	results := []T{}
+	seen := Set[K]{}
+
+	for _, s := range slice {
+		k := key(s)
+
+		if seen.Contains(k) {
+			continue
+		}
+
+		seen.Add(k)
+		results = append(results, s)
+	}
+
+	return results
+}
+
+// IntersectBy returns the items of left whose key also appears in right.
+func IntersectBy[T any, K comparable](left []T, right []T, key func(T) K) []T {
+	keys := NewSet(Map(right, key)...)
+
+	return Filter(left, func(item T) bool { return keys.Contains(key(item)) })
+}
+
+// DifferenceBy returns the items of left whose key does not appear in right.
+func DifferenceBy[T any, K comparable](left []T, right []T, key func(T) K) []T {
+	keys := NewSet(Map(right, key)...)
+
+	return Filter(left, func(item T) bool { return !keys.Contains(key(item)) })
+}
+
+func compareAny[T any](a, b T) int {
+	va := reflect.ValueOf(a)
+	vb := reflect.ValueOf(b)
+
+	if va.IsValid() && vb.IsValid() {
+		switch va.Kind() {
+		case reflect.String:
+			return cmp.Compare(va.String(), vb.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(va.Int(), vb.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(va.Uint(), vb.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(va.Float(), vb.Float())
+		case reflect.Bool:
+			return cmp.Compare(boolRank(va.Bool()), boolRank(vb.Bool()))
+		}
+	}
+
+	ta, _ := textOf(a)
+	tb, _ := textOf(b)
+
+	return cmp.Compare(ta, tb)
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func textOf[T any](item T) (string, error) {
+	if m, ok := any(item).(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	switch reflect.ValueOf(item).Kind() {
+	case reflect.String:
+		return reflect.ValueOf(item).String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		return fmt.Sprint(item), nil
+	}
+
+	data, err := json.Marshal(item)
+
+	return string(data), err
+}
+
+func parseText[T any](text string) (T, error) {
+	var result T
+
+	err := text_utils.DecodeText(reflect.ValueOf(&result).Elem(), text)
+
+	if errors.Is(err, text_utils.ErrUnsupportedType) {
+		return result, fmt.Errorf("Please implement encoding.TextUnmarshaler for type %v", reflect.TypeFor[T]().String())
+	}
+
+	return result, err
+}
+
+func quoteArrayElement(text string) string {
+	if text != "" && !strings.EqualFold(text, "null") && !strings.ContainsAny(text, `{}," \`) {
+		return text
+	}
+
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"`, `\"`)
+
+	return `"` + text + `"`
+}
+
+func parseArrayLiteral(literal string) ([]string, error) {
+	literal = strings.TrimSpace(literal)
+
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return nil, fmt.Errorf("invalid array literal %q", literal)
+	}
+
+	body := literal[1 : len(literal)-1]
+	elements := []string{}
+
+	if body == "" {
+		return elements, nil
+	}
+
+	var current strings.Builder
+	quoted, inQuotes, escaped := false, false, false
+
+	for _, r := range body {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			quoted = true
+		case r == ',' && !inQuotes:
+			elements = append(elements, arrayElement(current.String(), quoted))
+			current.Reset()
+			quoted = false
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("invalid array literal %q", literal)
+	}
+
+	elements = append(elements, arrayElement(current.String(), quoted))
+
+	return elements, nil
+}
+
+func arrayElement(text string, quoted bool) string {
+	if quoted {
+		return text
+	}
+
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,95 @@
+package slice_utils_test
+
+import (
+	"encoding/json"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+	"github.com/google/uuid"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Set", Label("Utils"), func() {
+	a := slice_utils.NewSet(1, 2, 3, 4)
+	b := slice_utils.NewSet(3, 4, 5)
+
+	It("checks membership", func() {
+		Expect(a.Contains(3)).To(BeTrue())
+		Expect(a.Contains(9)).To(BeFalse())
+		Expect(a.Len()).To(Equal(4))
+	})
+
+	It("supports the set operations", func() {
+		Expect(a.Union(b).Sorted()).To(Equal([]int{1, 2, 3, 4, 5}))
+		Expect(a.Intersect(b).Sorted()).To(Equal([]int{3, 4}))
+		Expect(a.Difference(b).Sorted()).To(Equal([]int{1, 2}))
+		Expect(a.SymmetricDifference(b).Sorted()).To(Equal([]int{1, 2, 5}))
+		Expect(slice_utils.NewSet(3, 4).IsSubset(a)).To(BeTrue())
+		Expect(b.IsSubset(a)).To(BeFalse())
+		Expect(a.IsSuperset(slice_utils.NewSet(1))).To(BeTrue())
+	})
+
+	It("does not mutate the operands", func() {
+		_ = a.Union(b)
+		Expect(a.Sorted()).To(Equal([]int{1, 2, 3, 4}))
+	})
+
+	It("iterates in sorted order", func() {
+		s := slice_utils.NewSet(10, 9, 100, 1)
+		Expect(slice_utils.Collect(s.All())).To(Equal([]int{1, 9, 10, 100}))
+	})
+
+	It("marshals to and from JSON", func() {
+		data, err := json.Marshal(slice_utils.NewSet("b", "a", "c"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`["a","b","c"]`))
+
+		var result slice_utils.Set[string]
+		Expect(json.Unmarshal([]byte(`["x","y","x"]`), &result)).To(Succeed())
+		Expect(result.Equal(slice_utils.NewSet("x", "y"))).To(BeTrue())
+	})
+
+	It("marshals to and from a SQL array", func() {
+		value, err := slice_utils.NewSet("b", "a c", `d"e`).Value()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(`{"a c",b,"d\"e"}`))
+
+		var result slice_utils.Set[string]
+		Expect(result.Scan([]byte(value.(string)))).To(Succeed())
+		Expect(result.Equal(slice_utils.NewSet("b", "a c", `d"e`))).To(BeTrue())
+
+		var numbers slice_utils.Set[int]
+		Expect(numbers.Scan("{3,1,2}")).To(Succeed())
+		Expect(numbers.Sorted()).To(Equal([]int{1, 2, 3}))
+
+		id := uuid.MustParse("6f1c1f2e-8a4e-4c53-9d4b-0c7d1c6f2a11")
+		var ids slice_utils.Set[uuid.UUID]
+		Expect(ids.Scan("{" + id.String() + "}")).To(Succeed())
+		Expect(ids.Contains(id)).To(BeTrue())
+
+		Expect(numbers.Scan("{1,x}")).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Uniq", Label("Utils"), func() {
+	type row struct {
+		ID   int
+		Name string
+	}
+
+	left := []row{{1, "a"}, {2, "b"}, {1, "c"}, {3, "d"}}
+	right := []row{{3, "x"}, {1, "y"}}
+
+	It("removes duplicates keeping the first occurrence", func() {
+		Expect(slice_utils.Uniq([]int{3, 1, 3, 2, 1})).To(Equal([]int{3, 1, 2}))
+		Expect(slice_utils.UniqBy(left, func(r row) int { return r.ID })).To(Equal([]row{{1, "a"}, {2, "b"}, {3, "d"}}))
+	})
+
+	It("intersects and differences by key", func() {
+		key := func(r row) int { return r.ID }
+
+		Expect(slice_utils.IntersectBy(left, right, key)).To(Equal([]row{{1, "a"}, {1, "c"}, {3, "d"}}))
+		Expect(slice_utils.DifferenceBy(left, right, key)).To(Equal([]row{{2, "b"}}))
+	})
+})
@@ -0,0 +1,138 @@
+package slice_utils
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"time"
+)
+
+// Comparator returns a negative number when a sorts before b, a positive
+// number when it sorts after and zero when both are equal.
+type Comparator[T any] func(a, b T) int
+
+func By[T any, K cmp.Ordered](key func(T) K) Comparator[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// ByFunc compares a key that is not cmp.Ordered, e.g. types.Null through
+// types.NullsFirst.
+func ByFunc[T any, K any](key func(T) K, compare func(a, b K) int) Comparator[T] {
+	return func(a, b T) int {
+		return compare(key(a), key(b))
+	}
+}
+
+// ByTime compares time based fields such as types.Date (d.Time) or
+// types.LocalTime (lt.Time()).
+func ByTime[T any](key func(T) time.Time) Comparator[T] {
+	return func(a, b T) int {
+		return key(a).Compare(key(b))
+	}
+}
+
+func (c Comparator[T]) ThenBy(next Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if res := c(a, b); res != 0 {
+			return res
+		}
+
+		return next(a, b)
+	}
+}
+
+func (c Comparator[T]) Reverse() Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// SortBy returns a sorted copy of the slice.
+func SortBy[T any](slice []T, compare Comparator[T]) []T {
+	result := slices.Clone(slice)
+	slices.SortFunc(result, compare)
+
+	return result
+}
+
+// SortStableBy returns a sorted copy of the slice keeping the original order
+// of equal items.
+func SortStableBy[T any](slice []T, compare Comparator[T]) []T {
+	result := slices.Clone(slice)
+	slices.SortStableFunc(result, compare)
+
+	return result
+}
+
+func MinBy[T any](slice []T, compare Comparator[T]) (T, bool) {
+	var result T
+
+	if len(slice) == 0 {
+		return result, false
+	}
+
+	return slices.MinFunc(slice, compare), true
+}
+
+func MaxBy[T any](slice []T, compare Comparator[T]) (T, bool) {
+	var result T
+
+	if len(slice) == 0 {
+		return result, false
+	}
+
+	return slices.MaxFunc(slice, compare), true
+}
+
+// TopK returns the k items that sort first under compare, in sorted order.
+// Use compare.Reverse() to get the largest items.
+func TopK[T any](slice []T, k int, compare Comparator[T]) []T {
+	if k <= 0 {
+		return []T{}
+	}
+
+	// Max-heap of the k best items seen so far: the root is the worst of them
+	// and gets replaced whenever a better item shows up.
+	h := &boundedHeap[T]{compare: compare.Reverse()}
+
+	for _, s := range slice {
+		if h.Len() < k {
+			heap.Push(h, s)
+			continue
+		}
+
+		if compare(s, h.items[0]) < 0 {
+			h.items[0] = s
+			heap.Fix(h, 0)
+		}
+	}
+
+	return SortBy(h.items, compare)
+}
+
+// BinarySearchBy searches a slice sorted ascending by key and returns the
+// position of target, or where it would be inserted, and whether it was found.
+func BinarySearchBy[T any, K cmp.Ordered](slice []T, target K, key func(T) K) (int, bool) {
+	return slices.BinarySearchFunc(slice, target, func(item T, target K) int {
+		return cmp.Compare(key(item), target)
+	})
+}
+
+type boundedHeap[T any] struct {
+	items   []T
+	compare Comparator[T]
+}
+
+func (h *boundedHeap[T]) Len() int           { return len(h.items) }
+func (h *boundedHeap[T]) Less(i, j int) bool { return h.compare(h.items[i], h.items[j]) < 0 }
+func (h *boundedHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *boundedHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+
+func (h *boundedHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+
+	return last
+}
@@ -0,0 +1,87 @@
+package slice_utils_test
+
+import (
+	"time"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sorting", Label("Utils"), func() {
+	type payment struct {
+		Account string
+		Amount  int
+		PaidAt  time.Time
+	}
+
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+
+	payments := []payment{
+		{"bca", 300, day(3)},
+		{"bni", 100, day(1)},
+		{"bca", 100, day(2)},
+		{"bni", 200, day(5)},
+		{"bca", 100, day(4)},
+	}
+
+	byAccount := slice_utils.By(func(p payment) string { return p.Account })
+	byAmount := slice_utils.By(func(p payment) int { return p.Amount })
+	byPaidAt := slice_utils.ByTime(func(p payment) time.Time { return p.PaidAt })
+
+	Context("SortBy()", func() {
+		It("sorts by several fields without mutating the input", func() {
+			result := slice_utils.SortBy(payments, byAccount.ThenBy(byAmount.Reverse()).ThenBy(byPaidAt))
+
+			Expect(result).To(Equal([]payment{payments[0], payments[2], payments[4], payments[3], payments[1]}))
+			Expect(payments[0].Amount).To(Equal(300))
+		})
+	})
+
+	Context("SortStableBy()", func() {
+		It("keeps the order of equal items", func() {
+			result := slice_utils.SortStableBy(payments, byAmount)
+
+			Expect(result).To(Equal([]payment{payments[1], payments[2], payments[4], payments[3], payments[0]}))
+		})
+	})
+
+	Context("MinBy() and MaxBy()", func() {
+		It("returns the extremes", func() {
+			earliest, found := slice_utils.MinBy(payments, byPaidAt)
+			Expect(found).To(BeTrue())
+			Expect(earliest).To(Equal(payments[1]))
+
+			latest, _ := slice_utils.MaxBy(payments, byPaidAt)
+			Expect(latest).To(Equal(payments[3]))
+
+			_, found = slice_utils.MaxBy([]payment{}, byPaidAt)
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("TopK()", func() {
+		It("returns the k first items in order", func() {
+			Expect(slice_utils.TopK([]int{5, 1, 9, 3, 7, 2}, 3, slice_utils.By(func(i int) int { return i }).Reverse())).
+				To(Equal([]int{9, 7, 5}))
+			Expect(slice_utils.TopK([]int{5, 1}, 3, slice_utils.By(func(i int) int { return i }))).To(Equal([]int{1, 5}))
+			Expect(slice_utils.TopK([]int{5, 1}, 0, slice_utils.By(func(i int) int { return i }))).To(BeEmpty())
+		})
+	})
+
+	Context("BinarySearchBy()", func() {
+		It("finds the item position", func() {
+			sorted := slice_utils.SortBy(payments, byPaidAt)
+			key := func(p payment) int64 { return p.PaidAt.Unix() }
+
+			idx, found := slice_utils.BinarySearchBy(sorted, day(4).Unix(), key)
+			Expect(found).To(BeTrue())
+			Expect(sorted[idx]).To(Equal(payments[4]))
+
+			idx, found = slice_utils.BinarySearchBy(sorted, day(6).Unix(), key)
+			Expect(found).To(BeFalse())
+			Expect(idx).To(Equal(5))
+		})
+	})
+})
@@ -0,0 +1,165 @@
+package slice_utils
+
+import "errors"
+
+type ErrorMode int
+
+const (
+	// StopOnFirstError returns as soon as a callback fails.
+	StopOnFirstError ErrorMode = iota
+	// CollectAllErrors skips failing items, keeps going and returns every
+	// failure joined with errors.Join.
+	CollectAllErrors
+)
+
+// Every error returned by the Try* functions wraps *IndexError, so the failing
+// position is available through errors.As.
+
+// TryMap keeps failing items as zero values in CollectAllErrors mode so the
+// result stays aligned with the input.
+func TryMap[T any, R any](slice []T, modifier func(i T) (R, error), mode ...ErrorMode) ([]R, error) {
+	results := []R{}
+	collector := newErrorCollector(mode)
+
+	for idx, s := range slice {
+		res, err := modifier(s)
+
+		if err != nil {
+			if collector.add(idx, err) {
+				return nil, collector.err()
+			}
+
+			var zero R
+			res = zero
+		}
+
+		results = append(results, res)
+	}
+
+	return results, collector.err()
+}
+
+func TryFilter[T any](slice []T, filterFn func(T) (bool, error), mode ...ErrorMode) ([]T, error) {
+	results := []T{}
+	collector := newErrorCollector(mode)
+
+	for idx, s := range slice {
+		keep, err := filterFn(s)
+
+		if err != nil {
+			if collector.add(idx, err) {
+				return nil, collector.err()
+			}
+
+			continue
+		}
+
+		if keep {
+			results = append(results, s)
+		}
+	}
+
+	return results, collector.err()
+}
+
+// TryReduce returns the value accumulated so far alongside the error.
+func TryReduce[T any, C any](slice []T, initial C, reducer func(C, T) (C, error), mode ...ErrorMode) (C, error) {
+	result := initial
+	collector := newErrorCollector(mode)
+
+	for idx, s := range slice {
+		next, err := reducer(result, s)
+
+		if err != nil {
+			if collector.add(idx, err) {
+				return result, collector.err()
+			}
+
+			continue
+		}
+
+		result = next
+	}
+
+	return result, collector.err()
+}
+
+func TryGroupBy[T any, C comparable](slice []T, comparison func(T) (C, error), mode ...ErrorMode) (map[C][]T, error) {
+	result := map[C][]T{}
+	collector := newErrorCollector(mode)
+
+	for idx, s := range slice {
+		comparison_result, err := comparison(s)
+
+		if err != nil {
+			if collector.add(idx, err) {
+				return nil, collector.err()
+			}
+
+			continue
+		}
+
+		result[comparison_result] = append(result[comparison_result], s)
+	}
+
+	return result, collector.err()
+}
+
+// TryFindBy in CollectAllErrors mode returns the found item together with the
+// errors raised by the items checked before it.
+func TryFindBy[T any](s []T, predicate func(T) (bool, error), mode ...ErrorMode) (T, bool, error) {
+	var result T
+	collector := newErrorCollector(mode)
+
+	for idx, item := range s {
+		found, err := predicate(item)
+
+		if err != nil {
+			if collector.add(idx, err) {
+				return result, false, collector.err()
+			}
+
+			continue
+		}
+
+		if found {
+			return item, true, collector.err()
+		}
+	}
+
+	return result, false, collector.err()
+}
+
+type errorCollector struct {
+	mode ErrorMode
+	errs []error
+}
+
+func newErrorCollector(mode []ErrorMode) *errorCollector {
+	collector := &errorCollector{mode: StopOnFirstError}
+
+	if len(mode) > 0 {
+		collector.mode = mode[0]
+	}
+
+	return collector
+}
+
+// add records the error and reports whether processing must stop.
+func (c *errorCollector) add(idx int, err error) bool {
+	c.errs = append(c.errs, &IndexError{Index: idx, Err: err})
+
+	return c.mode == StopOnFirstError
+}
+
+func (c *errorCollector) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+
+	if c.mode == StopOnFirstError {
+		return c.errs[0]
+	}
+
+	return errors.Join(c.errs...)
+}
@@ -0,0 +1,167 @@
+package slice_utils_test
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Try", Label("Utils"), func() {
+	valid := []string{"1", "2", "3", "4"}
+	invalid := []string{"1", "x", "3", "y"}
+
+	failingIndex := func(err error) int {
+		var indexErr *slice_utils.IndexError
+		Expect(errors.As(err, &indexErr)).To(BeTrue())
+		return indexErr.Index
+	}
+
+	Context("TryMap()", func() {
+		It("transforms the slices", func() {
+			result, err := slice_utils.TryMap(valid, strconv.Atoi)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal([]int{1, 2, 3, 4}))
+		})
+
+		It("stops at the first error with the failing index", func() {
+			result, err := slice_utils.TryMap(invalid, strconv.Atoi)
+
+			Expect(result).To(BeNil())
+			Expect(err).To(MatchError(strconv.ErrSyntax))
+			Expect(failingIndex(err)).To(Equal(1))
+		})
+
+		It("aggregates every failure", func() {
+			result, err := slice_utils.TryMap(invalid, strconv.Atoi, slice_utils.CollectAllErrors)
+
+			Expect(result).To(Equal([]int{1, 0, 3, 0}))
+			errs := err.(interface{ Unwrap() []error }).Unwrap()
+			Expect(errs).To(HaveLen(2))
+			Expect(failingIndex(errs[0])).To(Equal(1))
+			Expect(failingIndex(errs[1])).To(Equal(3))
+		})
+
+		It("keeps failing items as zero values whatever the callback returned", func() {
+			result, err := slice_utils.TryMap([]int{1, -2, 3}, func(i int) (int, error) {
+				if i < 0 {
+					return i, errors.New("negative")
+				}
+
+				return i * 10, nil
+			}, slice_utils.CollectAllErrors)
+
+			Expect(err).To(MatchError("index 1: negative"))
+			Expect(result).To(Equal([]int{10, 0, 30}))
+		})
+	})
+
+	Context("TryFilter()", func() {
+		isEven := func(s string) (bool, error) {
+			i, err := strconv.Atoi(s)
+			return i%2 == 0, err
+		}
+
+		It("filters the data", func() {
+			result, err := slice_utils.TryFilter(valid, isEven)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal([]string{"2", "4"}))
+		})
+
+		It("skips failing items when collecting errors", func() {
+			result, err := slice_utils.TryFilter([]string{"2", "x", "4"}, isEven, slice_utils.CollectAllErrors)
+
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(Equal([]string{"2", "4"}))
+		})
+	})
+
+	Context("TryReduce()", func() {
+		sum := func(acc int, s string) (int, error) {
+			i, err := strconv.Atoi(s)
+			return acc + i, err
+		}
+
+		It("reduces slice into final data", func() {
+			result, err := slice_utils.TryReduce(valid, 0, sum)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(10))
+		})
+
+		It("returns the partial result on failure", func() {
+			result, err := slice_utils.TryReduce(invalid, 0, sum)
+
+			Expect(failingIndex(err)).To(Equal(1))
+			Expect(result).To(Equal(1))
+
+			result, err = slice_utils.TryReduce(invalid, 0, sum, slice_utils.CollectAllErrors)
+
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(Equal(4))
+		})
+	})
+
+	Context("TryGroupBy()", func() {
+		parity := func(s string) (string, error) {
+			i, err := strconv.Atoi(s)
+			if i%2 == 0 {
+				return "even", err
+			}
+			return "odd", err
+		}
+
+		It("groups the data", func() {
+			result, err := slice_utils.TryGroupBy(valid, parity)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(map[string][]string{
+				"odd":  {"1", "3"},
+				"even": {"2", "4"},
+			}))
+		})
+
+		It("returns nil on the first error", func() {
+			result, err := slice_utils.TryGroupBy(invalid, parity)
+
+			Expect(result).To(BeNil())
+			Expect(failingIndex(err)).To(Equal(1))
+		})
+	})
+
+	Context("TryFindBy()", func() {
+		isThree := func(s string) (bool, error) {
+			i, err := strconv.Atoi(s)
+			return i == 3, err
+		}
+
+		It("returns the item and true when exists", func() {
+			result, found, err := slice_utils.TryFindBy(valid, isThree)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(result).To(Equal("3"))
+		})
+
+		It("stops at the first error", func() {
+			result, found, err := slice_utils.TryFindBy(invalid, isThree)
+
+			Expect(failingIndex(err)).To(Equal(1))
+			Expect(found).To(BeFalse())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("keeps searching when collecting errors", func() {
+			result, found, err := slice_utils.TryFindBy(invalid, isThree, slice_utils.CollectAllErrors)
+
+			Expect(err).To(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(result).To(Equal("3"))
+		})
+	})
+})
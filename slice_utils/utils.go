@@ -52,8 +52,12 @@ func Filter[T any](slice []T, filterFn func(T) bool) []T {
 	return results
 }
 
+// Get safely accesses the slice. Negative indexes count from the end, so -1
+// is the last item.
 func Get[T any](s []T, idx int) (T, bool) {
-	if idx >= len(s) {
+	idx, ok := resolveIndex(len(s), idx)
+
+	if !ok {
 		var result T
 		return result, false
 	}
@@ -61,6 +65,57 @@ func Get[T any](s []T, idx int) (T, bool) {
 	return s[idx], true
 }
 
+func GetOr[T any](s []T, idx int, fallback T) T {
+	if result, ok := Get(s, idx); ok {
+		return result
+	}
+
+	return fallback
+}
+
+func Last[T any](s []T) *T {
+	return Nth(s, -1)
+}
+
+// Nth returns a pointer to a copy of the item at idx, like First, or nil when
+// idx is out of range. Negative indexes count from the end.
+func Nth[T any](s []T, idx int) *T {
+	if result, ok := Get(s, idx); ok {
+		return &result
+	}
+
+	return nil
+}
+
+// Slice returns s[start:end] with Python-style negative indexes, clamping
+// both bounds to the slice instead of panicking.
+func Slice[T any](s []T, start int, end int) []T {
+	start = clampIndex(len(s), start)
+	end = clampIndex(len(s), end)
+
+	if start >= end {
+		return []T{}
+	}
+
+	return s[start:end]
+}
+
+func resolveIndex(length int, idx int) (int, bool) {
+	if idx < 0 {
+		idx += length
+	}
+
+	return idx, idx >= 0 && idx < length
+}
+
+func clampIndex(length int, idx int) int {
+	if idx < 0 {
+		idx += length
+	}
+
+	return min(max(idx, 0), length)
+}
+
 func FindBy[T any](s []T, predicate func(T) bool) (T, bool) {
 	var result T
 	found := false
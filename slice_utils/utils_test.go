@@ -79,6 +79,59 @@ var _ = Describe("Slice Utils", Label("Utils"), func() {
 			Expect(exists).To(BeTrue())
 			Expect(res).To(Equal(9))
 		})
+
+		It("supports negative indexes", func() {
+			s := []int{1, 2, 3}
+
+			res, exists := slice_utils.Get(s, -1)
+			Expect(exists).To(BeTrue())
+			Expect(res).To(Equal(3))
+
+			res, exists = slice_utils.Get(s, -3)
+			Expect(exists).To(BeTrue())
+			Expect(res).To(Equal(1))
+
+			res, exists = slice_utils.Get(s, -4)
+			Expect(exists).To(BeFalse())
+			Expect(res).To(Equal(0))
+
+			_, exists = slice_utils.Get([]int{}, -1)
+			Expect(exists).To(BeFalse())
+		})
+	})
+
+	Context("GetOr", func() {
+		It("returns the fallback when out of range", func() {
+			Expect(slice_utils.GetOr([]int{1, 2}, 1, 7)).To(Equal(2))
+			Expect(slice_utils.GetOr([]int{1, 2}, 2, 7)).To(Equal(7))
+			Expect(slice_utils.GetOr([]int{1, 2}, -3, 7)).To(Equal(7))
+		})
+	})
+
+	Context("Last() and Nth()", func() {
+		It("returns a pointer to the item or nil", func() {
+			three := 3
+			two := 2
+
+			Expect(slice_utils.Last([]int{1, 2, 3})).To(Equal(&three))
+			Expect(slice_utils.Last([]int{})).To(BeNil())
+			Expect(slice_utils.Nth([]int{1, 2, 3}, 1)).To(Equal(&two))
+			Expect(slice_utils.Nth([]int{1, 2, 3}, -2)).To(Equal(&two))
+			Expect(slice_utils.Nth([]int{1, 2, 3}, 5)).To(BeNil())
+		})
+	})
+
+	Context("Slice", func() {
+		s := []int{1, 2, 3, 4, 5}
+
+		It("clamps the bounds", func() {
+			Expect(slice_utils.Slice(s, 1, 3)).To(Equal([]int{2, 3}))
+			Expect(slice_utils.Slice(s, -2, 100)).To(Equal([]int{4, 5}))
+			Expect(slice_utils.Slice(s, -100, 2)).To(Equal([]int{1, 2}))
+			Expect(slice_utils.Slice(s, 0, -1)).To(Equal([]int{1, 2, 3, 4}))
+			Expect(slice_utils.Slice(s, 4, 2)).To(BeEmpty())
+			Expect(slice_utils.Slice([]int{}, 0, 3)).To(BeEmpty())
+		})
 	})
 
 	Describe("FindBy", func() {
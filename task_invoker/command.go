@@ -0,0 +1,219 @@
+package task_invoker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// CommandResult is printed by the task subcommands of BuildCommand when run
+// with --json.
+type CommandResult struct {
+	Task     string        `json:"task"`
+	Outcome  RunOutcome    `json:"outcome"`
+	ExitCode int           `json:"exit_code"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Result   any           `json:"result,omitempty"`
+	// Plan holds the execution stages of a --dry-run.
+	Plan [][]string `json:"plan,omitempty"`
+}
+
+// BuildCommand adds one subcommand per registered task to Cmd, or to a new
+// root command named after the executable when Cmd is nil, and returns the
+// root. The root gets the --dry-run and --json persistent flags, and a
+// history subcommand when a recorder implements HistoryReader; task names are
+// completed by the cobra completion command. Tasks must be registered
+// before calling BuildCommand, calling it again adds the tasks registered
+// since. Execute turns the error into an exit code.
+func BuildCommand(invoker *Invoker) *cobra.Command {
+	root := invoker.Cmd
+
+	if root == nil {
+		root = &cobra.Command{Use: filepath.Base(os.Args[0])}
+		invoker.Cmd = root
+	}
+
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	dryRun := boolFlag(root.PersistentFlags(), "dry-run", invoker.DryRun, "print the execution plan instead of running the task")
+	asJSON := boolFlag(root.PersistentFlags(), "json", false, "print the outcome as JSON")
+
+	for _, name := range invoker.TaskNames() {
+		task := invoker.tasks[name]
+
+		if hasSubcommand(root, name) {
+			continue
+		}
+
+		root.AddCommand(task.command(nil, func(tc *TaskContext) error {
+			if task.rawArgs() {
+				// Flag parsing is disabled for raw tasks, the root flags
+				// arrive as arguments.
+				args, rawDryRun, rawJSON, err := stripRootFlags(tc.Args, dryRun(), asJSON())
+
+				if err != nil {
+					return err
+				}
+
+				tc.Args = args
+
+				return invoker.runCommand(tc, rawDryRun, rawJSON)
+			}
+
+			return invoker.runCommand(tc, dryRun(), asJSON())
+		}))
+	}
+
+	for _, recorder := range invoker.Recorders {
+		reader, ok := recorder.(HistoryReader)
+
+		if ok && !hasSubcommand(root, "history") {
+			root.AddCommand(historyCommand(reader, asJSON))
+		}
+	}
+
+	return root
+}
+
+// boolFlag defines the flag unless a previous call did and returns its
+// current value getter.
+func boolFlag(flags *pflag.FlagSet, name string, value bool, usage string) func() bool {
+	if flags.Lookup(name) == nil {
+		flags.Bool(name, value, usage)
+	}
+
+	return func() bool {
+		value, _ := flags.GetBool(name)
+		return value
+	}
+}
+
+func hasSubcommand(cmd *cobra.Command, name string) bool {
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == name || sub.HasAlias(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stripRootFlags removes --dry-run and --json, with or without an =value,
+// from the raw arguments before the "--" terminator, returning their values.
+func stripRootFlags(args []string, dryRun bool, asJSON bool) ([]string, bool, bool, error) {
+	results := []string{}
+	targets := map[string]*bool{"--dry-run": &dryRun, "--json": &asJSON}
+
+	for idx, arg := range args {
+		if arg == "--" {
+			return append(results, args[idx:]...), dryRun, asJSON, nil
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		target, found := targets[name]
+
+		if !found {
+			results = append(results, arg)
+			continue
+		}
+
+		*target = true
+
+		if hasValue {
+			parsed, err := strconv.ParseBool(value)
+
+			if err != nil {
+				return nil, false, false, fmt.Errorf("invalid argument %q for %q flag: %w", value, name, err)
+			}
+
+			*target = parsed
+		}
+	}
+
+	return results, dryRun, asJSON, nil
+}
+
+func (i *Invoker) runCommand(tc *TaskContext, dryRun bool, asJSON bool) error {
+	if dryRun {
+		if !asJSON {
+			return i.PrintPlan(tc.Out, tc.Name)
+		}
+
+		plan, err := i.Plan(tc.Name)
+
+		if err != nil {
+			return err
+		}
+
+		return writeJSON(tc.Out, CommandResult{Task: tc.Name, Outcome: OutcomeSucceeded, Plan: plan})
+	}
+
+	start := time.Now()
+	err := i.withSignals(tc, tc.Name, func(ctx context.Context) error {
+		taskTc := *tc
+		taskTc.Context = ctx
+
+		if len(tc.task.DependsOn) > 0 {
+			return i.runGraph(ctx, tc.Name, func(ctx context.Context) error {
+				taskTc.Context = ctx
+				return i.invoke(&taskTc)
+			})
+		}
+
+		return i.invoke(&taskTc)
+	})
+
+	if !asJSON {
+		return err
+	}
+
+	result := CommandResult{
+		Task:     tc.Name,
+		Outcome:  outcomeOf(err),
+		ExitCode: ExitCode(err),
+		Duration: time.Since(start),
+		Result:   tc.result.value,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	if jsonErr := writeJSON(tc.Out, result); jsonErr != nil {
+		return jsonErr
+	}
+
+	return err
+}
+
+func writeJSON(out io.Writer, value any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+// Execute runs cmd, prints the error to its error output and returns the exit
+// code to hand to os.Exit:
+//
+//	os.Exit(task_invoker.Execute(ctx, task_invoker.BuildCommand(invoker)))
+func Execute(ctx context.Context, cmd *cobra.Command) int {
+	err := cmd.ExecuteContext(ctx)
+
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err.Error())
+	}
+
+	return ExitCode(err)
+}
@@ -0,0 +1,184 @@
+package task_invoker_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildCommand", func() {
+	var (
+		out   *bytes.Buffer
+		errs  *bytes.Buffer
+		calls []string
+	)
+
+	build := func(args ...string) *cobra.Command {
+		invoker := task_invoker.NewInvoker("", &cobra.Command{Use: "app"}, nil)
+		invoker.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+		invoker.Signals = nil
+
+		invoker.AddTask(task_invoker.Task{
+			Name: "migrate",
+			Run: func(tc *task_invoker.TaskContext) error {
+				calls = append(calls, "migrate")
+				return nil
+			},
+		})
+		invoker.AddTask(task_invoker.Task{
+			Name:        "import",
+			Description: "Imports a file",
+			DependsOn:   []string{"migrate"},
+			Args:        []task_invoker.TaskArg{{Name: "file"}},
+			Flags: func(flags *pflag.FlagSet) {
+				flags.Int("batch", 10, "batch size")
+			},
+			Run: func(tc *task_invoker.TaskContext) error {
+				batch, _ := tc.Flags.GetInt("batch")
+				calls = append(calls, "import")
+				tc.SetResult(map[string]any{"file": tc.Arg("file"), "batch": batch})
+				return nil
+			},
+		})
+		invoker.AddTask(task_invoker.Task{
+			Name: "echo",
+			Run: func(tc *task_invoker.TaskContext) error {
+				calls = append(calls, tc.Args...)
+				return nil
+			},
+		})
+		invoker.AddTask(task_invoker.Task{
+			Name:    "slow",
+			Timeout: time.Millisecond,
+			Run: func(tc *task_invoker.TaskContext) error {
+				<-tc.Done()
+				return tc.Err()
+			},
+		})
+
+		cmd := task_invoker.BuildCommand(invoker)
+		cmd.SetOut(out)
+		cmd.SetErr(errs)
+		cmd.SetArgs(args)
+
+		return cmd
+	}
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		errs = &bytes.Buffer{}
+		calls = []string{}
+	})
+
+	It("creates a subcommand per task", func() {
+		names := []string{}
+
+		for _, sub := range build().Commands() {
+			names = append(names, sub.Name())
+		}
+
+		Expect(names).To(ContainElements("echo", "import", "migrate", "slow"))
+	})
+
+	It("runs the task after its dependencies with the parsed arguments", func(ctx SpecContext) {
+		Expect(task_invoker.Execute(ctx, build("import", "bookings.csv", "--batch", "50"))).To(Equal(task_invoker.ExitSuccess))
+		Expect(calls).To(Equal([]string{"migrate", "import"}))
+	})
+
+	It("prints the outcome as JSON", func(ctx SpecContext) {
+		Expect(task_invoker.Execute(ctx, build("--json", "import", "bookings.csv"))).To(Equal(task_invoker.ExitSuccess))
+
+		var result task_invoker.CommandResult
+		Expect(json.Unmarshal(out.Bytes(), &result)).To(Succeed())
+		Expect(result.Task).To(Equal("import"))
+		Expect(result.Outcome).To(Equal(task_invoker.OutcomeSucceeded))
+		Expect(result.Result).To(Equal(map[string]any{"file": "bookings.csv", "batch": float64(10)}))
+	})
+
+	It("prints the plan on --dry-run", func(ctx SpecContext) {
+		Expect(task_invoker.Execute(ctx, build("import", "bookings.csv", "--dry-run"))).To(Equal(task_invoker.ExitSuccess))
+		Expect(calls).To(BeEmpty())
+		Expect(out.String()).To(Equal("Execution plan for import:\n  1. migrate\n  2. import\n"))
+
+		out.Reset()
+		Expect(task_invoker.Execute(ctx, build("import", "x", "--dry-run", "--json"))).To(Equal(task_invoker.ExitSuccess))
+
+		var result task_invoker.CommandResult
+		Expect(json.Unmarshal(out.Bytes(), &result)).To(Succeed())
+		Expect(result.Plan).To(Equal([][]string{{"migrate"}, {"import"}}))
+	})
+
+	It("derives the exit code from the error", func(ctx SpecContext) {
+		Expect(task_invoker.Execute(ctx, build("slow", "--json"))).To(Equal(task_invoker.ExitTimeout))
+
+		var result task_invoker.CommandResult
+		Expect(json.Unmarshal(out.Bytes(), &result)).To(Succeed())
+		Expect(result.Outcome).To(Equal(task_invoker.OutcomeTimedOut))
+		Expect(result.ExitCode).To(Equal(task_invoker.ExitTimeout))
+		Expect(errs.String()).To(ContainSubstring(context.DeadlineExceeded.Error()))
+
+		Expect(task_invoker.Execute(ctx, build("import"))).To(Equal(task_invoker.ExitFailure))
+		Expect(errs.String()).To(ContainSubstring("accepts between 1 and 1 arg(s)"))
+	})
+
+	It("takes the root flags out of the raw arguments", func(ctx SpecContext) {
+		args := []string{"echo", "a", "--json=true", "b", "--dry-run=false", "--", "--json"}
+		Expect(task_invoker.Execute(ctx, build(args...))).To(Equal(task_invoker.ExitSuccess))
+		Expect(calls).To(Equal([]string{"a", "b", "--", "--json"}))
+
+		var result task_invoker.CommandResult
+		Expect(json.Unmarshal(out.Bytes(), &result)).To(Succeed())
+		Expect(result.Task).To(Equal("echo"))
+
+		out.Reset()
+		calls = []string{}
+		Expect(task_invoker.Execute(ctx, build("echo", "--json=false", "--dry-run"))).To(Equal(task_invoker.ExitSuccess))
+		Expect(calls).To(BeEmpty())
+		Expect(out.String()).To(HavePrefix("Execution plan for echo:"))
+
+		Expect(task_invoker.Execute(ctx, build("echo", "--json=maybe"))).To(Equal(task_invoker.ExitFailure))
+		Expect(errs.String()).To(ContainSubstring(`invalid argument "maybe" for "--json" flag`))
+	})
+
+	It("can be built again for tasks registered later", func(ctx SpecContext) {
+		invoker := task_invoker.NewInvoker("", &cobra.Command{Use: "app"}, nil)
+		invoker.Add("first", func() error { return nil })
+		task_invoker.BuildCommand(invoker)
+
+		invoker.Add("second", func() error {
+			calls = append(calls, "second")
+			return nil
+		})
+
+		cmd := task_invoker.BuildCommand(invoker)
+		Expect(cmd.Commands()).To(HaveLen(2))
+
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"second", "--json"})
+		Expect(task_invoker.Execute(ctx, cmd)).To(Equal(task_invoker.ExitSuccess))
+		Expect(calls).To(Equal([]string{"second"}))
+		Expect(out.String()).To(ContainSubstring(`"task": "second"`))
+	})
+
+	It("completes the task names", func(ctx SpecContext) {
+		cmd := build(cobra.ShellCompRequestCmd, "im")
+		Expect(cmd.ExecuteContext(ctx)).To(Succeed())
+		Expect(out.String()).To(HavePrefix("import\tImports a file\n"))
+	})
+
+	It("returns unknown commands as failures", func(ctx SpecContext) {
+		Expect(task_invoker.Execute(ctx, build("imprt"))).To(Equal(task_invoker.ExitFailure))
+		Expect(errors.New(errs.String())).To(MatchError(ContainSubstring("unknown command")))
+	})
+})
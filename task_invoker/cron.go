@@ -0,0 +1,252 @@
+package task_invoker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed cron expression with a seconds field:
+//
+//	second minute hour day-of-month month day-of-week
+//
+// Five field expressions run at second zero. Fields accept *, ?, lists,
+// ranges, steps and month or weekday names. The @yearly, @monthly, @weekly,
+// @daily and @hourly descriptors are supported, and a CRON_TZ= or TZ= prefix
+// selects the time zone, e.g. "CRON_TZ=Asia/Makassar 0 30 2 * * *".
+type CronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+	Location                              *time.Location
+}
+
+type cronBounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondBounds = cronBounds{0, 59, nil}
+	minuteBounds = cronBounds{0, 59, nil}
+	hourBounds   = cronBounds{0, 23, nil}
+	domBounds    = cronBounds{1, 31, nil}
+	monthBounds  = cronBounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = cronBounds{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+func ParseCron(spec string) (*CronSchedule, error) {
+	schedule := &CronSchedule{Location: time.Local}
+	spec = strings.TrimSpace(spec)
+
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if !strings.HasPrefix(spec, prefix) {
+			continue
+		}
+
+		zone, rest, _ := strings.Cut(strings.TrimPrefix(spec, prefix), " ")
+		location, err := time.LoadLocation(zone)
+
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", spec, err)
+		}
+
+		schedule.Location = location
+		spec = strings.TrimSpace(rest)
+	}
+
+	if descriptor, found := cronDescriptors[strings.ToLower(spec)]; found {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+
+	if len(fields) == 5 {
+		fields = append([]string{"0"}, fields...)
+	}
+
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("cron %q: expected 5 or 6 fields, got %d", spec, len(fields))
+	}
+
+	var err error
+
+	targets := []struct {
+		bits   *uint64
+		star   *bool
+		bounds cronBounds
+	}{
+		{&schedule.second, nil, secondBounds},
+		{&schedule.minute, nil, minuteBounds},
+		{&schedule.hour, nil, hourBounds},
+		{&schedule.dom, &schedule.domStar, domBounds},
+		{&schedule.month, nil, monthBounds},
+		{&schedule.dow, &schedule.dowStar, dowBounds},
+	}
+
+	for idx, target := range targets {
+		var star bool
+
+		*target.bits, star, err = parseCronField(fields[idx], target.bounds)
+
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", spec, err)
+		}
+
+		if target.star != nil {
+			*target.star = star
+		}
+	}
+
+	// Sunday may be written as 7.
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	return schedule, nil
+}
+
+func parseCronField(field string, bounds cronBounds) (uint64, bool, error) {
+	var bits uint64
+
+	star := field == "*" || field == "?"
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		start, end := bounds.min, bounds.max
+
+		if rangePart != "*" && rangePart != "?" {
+			low, high, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+
+			if start, err = parseCronValue(low, bounds); err != nil {
+				return 0, false, err
+			}
+
+			end = start
+
+			if isRange {
+				if end, err = parseCronValue(high, bounds); err != nil {
+					return 0, false, err
+				}
+			} else if hasStep {
+				end = bounds.max
+			}
+		}
+
+		step := 1
+
+		if hasStep {
+			var err error
+
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return 0, false, fmt.Errorf("invalid step %q", part)
+			}
+		}
+
+		if start > end {
+			return 0, false, fmt.Errorf("invalid range %q", part)
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+
+	return bits, star, nil
+}
+
+func parseCronValue(text string, bounds cronBounds) (int, error) {
+	if value, found := bounds.names[strings.ToLower(text)]; found {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(text)
+
+	if err != nil || value < bounds.min || value > bounds.max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", text, bounds.min, bounds.max)
+	}
+
+	return value, nil
+}
+
+// Next returns the first activation strictly after t, in the schedule
+// location, or the zero time when there is none within five years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.Location).Truncate(time.Second).Add(time.Second)
+	limit := t.Year() + 5
+
+wrap:
+	if t.Year() > limit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.Location)
+
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.Location)
+
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.Location)
+
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.second&(1<<uint(t.Second())) == 0 {
+		t = t.Add(time.Second)
+
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
@@ -0,0 +1,52 @@
+package task_invoker_test
+
+import (
+	"time"
+
+	"github.com/Nuanu-com/go-utils/task_invoker"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseCron", func() {
+	makassar, _ := time.LoadLocation("Asia/Makassar")
+	at := func(layout string) time.Time {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", layout, makassar)
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+
+	DescribeTable("Next",
+		func(spec string, from string, expected string) {
+			schedule, err := task_invoker.ParseCron("CRON_TZ=Asia/Makassar " + spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Next(at(from))).To(Equal(at(expected)))
+		},
+		Entry("every second", "* * * * * *", "2025-07-01 10:00:00", "2025-07-01 10:00:01"),
+		Entry("every 15 seconds", "*/15 * * * * *", "2025-07-01 10:00:14", "2025-07-01 10:00:15"),
+		Entry("five fields run at second zero", "30 2 * * *", "2025-07-01 10:00:00", "2025-07-02 02:30:00"),
+		Entry("ranges and lists", "0 0 9-17/4 * * mon,fri", "2025-07-01 10:00:00", "2025-07-04 09:00:00"),
+		Entry("month rollover", "0 0 0 31 * *", "2025-07-31 10:00:00", "2025-08-31 00:00:00"),
+		Entry("year rollover", "0 0 0 1 jan *", "2025-07-01 10:00:00", "2026-01-01 00:00:00"),
+		Entry("leap day", "0 0 0 29 2 *", "2025-03-01 00:00:00", "2028-02-29 00:00:00"),
+		Entry("day of month or week", "0 0 0 1 * 0", "2025-07-01 10:00:00", "2025-07-06 00:00:00"),
+		Entry("sunday as seven", "0 0 0 * * 7", "2025-07-01 10:00:00", "2025-07-06 00:00:00"),
+		Entry("descriptor", "@daily", "2025-07-01 10:00:00", "2025-07-02 00:00:00"),
+	)
+
+	It("uses the time zone of the expression", func() {
+		schedule, err := task_invoker.ParseCron("CRON_TZ=Asia/Makassar 0 0 2 * * *")
+		Expect(err).NotTo(HaveOccurred())
+
+		next := schedule.Next(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+		Expect(next.UTC()).To(Equal(time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC)))
+	})
+
+	It("rejects invalid expressions", func() {
+		for _, spec := range []string{"* * *", "60 * * * * *", "* * * * 13 *", "*/0 * * * * *", "5-1 * * * * *", "TZ=Nowhere/City * * * * *"} {
+			_, err := task_invoker.ParseCron(spec)
+			Expect(err).To(HaveOccurred(), spec)
+		}
+	})
+})
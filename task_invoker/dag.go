@@ -0,0 +1,321 @@
+package task_invoker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("task dependency cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// TaskError wraps the failure of one task of a dependency graph.
+type TaskError struct {
+	Task string
+	Err  error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("task %s: %s", e.Task, e.Err.Error())
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+type SkippedError struct {
+	Task  string
+	Cause string
+}
+
+func (e *SkippedError) Error() string {
+	return fmt.Sprintf("task %s skipped: %s", e.Task, e.Cause)
+}
+
+// Plan resolves the dependencies of the named tasks into stages. The tasks of
+// a stage only depend on earlier stages and may run in parallel.
+func (i *Invoker) Plan(names ...string) ([][]string, error) {
+	graph, err := i.resolve(names)
+
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := map[string]int{}
+
+	for name, task := range graph {
+		remaining[name] = dependencyCount(task)
+	}
+
+	stages := [][]string{}
+
+	for len(remaining) > 0 {
+		stage := []string{}
+
+		for name, count := range remaining {
+			if count == 0 {
+				stage = append(stage, name)
+			}
+		}
+
+		if len(stage) == 0 {
+			return nil, stalledError(slices.Collect(maps.Keys(remaining)))
+		}
+
+		slices.Sort(stage)
+
+		for _, name := range stage {
+			delete(remaining, name)
+
+			for _, dependent := range dependentsOf(graph, name) {
+				remaining[dependent]--
+			}
+		}
+
+		stages = append(stages, stage)
+	}
+
+	return stages, nil
+}
+
+func (i *Invoker) PrintPlan(out io.Writer, names ...string) error {
+	stages, err := i.Plan(names...)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Execution plan for %s:\n", strings.Join(names, ", "))
+
+	for idx, stage := range stages {
+		fmt.Fprintf(out, "  %d. %s\n", idx+1, strings.Join(stage, ", "))
+	}
+
+	return nil
+}
+
+// resolve collects the named tasks and their transitive dependencies,
+// failing on unknown tasks and cycles.
+func (i *Invoker) resolve(names []string) (map[string]*Task, error) {
+	graph := map[string]*Task{}
+	visiting := map[string]bool{}
+	path := []string{}
+
+	var visit func(name string) error
+
+	visit = func(name string) error {
+		if visiting[name] {
+			start := slices.Index(path, name)
+			return &CycleError{Cycle: append(slices.Clone(path[start:]), name)}
+		}
+
+		if _, done := graph[name]; done {
+			return nil
+		}
+
+		task, err := i.Task(name)
+
+		if err != nil {
+			return err
+		}
+
+		visiting[name] = true
+		path = append(path, name)
+
+		for _, dependency := range task.DependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		visiting[name] = false
+		graph[name] = task
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return graph, nil
+}
+
+// dependencyCount counts the distinct dependencies of task, which is how
+// often dependentsOf lists it.
+func dependencyCount(task *Task) int {
+	return len(slices.Compact(slices.Sorted(slices.Values(task.DependsOn))))
+}
+
+func stalledError(names []string) error {
+	slices.Sort(names)
+
+	return fmt.Errorf("task dependency graph stalled before %s", strings.Join(names, ", "))
+}
+
+func dependentsOf(graph map[string]*Task, name string) []string {
+	results := []string{}
+
+	for other, task := range graph {
+		if slices.Contains(task.DependsOn, name) {
+			results = append(results, other)
+		}
+	}
+
+	slices.Sort(results)
+
+	return results
+}
+
+type graphResult struct {
+	name string
+	err  error
+}
+
+// runGraph runs target after its dependencies. Independent tasks run in
+// parallel up to Parallelism; when a task fails every task depending on it is
+// skipped while unrelated tasks keep running. The dependencies run without
+// arguments while target runs through run.
+func (i *Invoker) runGraph(ctx context.Context, target string, run func(ctx context.Context) error) error {
+	graph, err := i.resolve([]string{target})
+
+	if err != nil {
+		return err
+	}
+
+	limit := max(i.Parallelism, 1)
+	remaining := map[string]int{}
+	skipped := map[string]string{}
+	ready := []string{}
+
+	for name, task := range graph {
+		remaining[name] = dependencyCount(task)
+
+		if remaining[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	slices.Sort(ready)
+
+	var skip func(name string, cause string)
+
+	skip = func(name string, cause string) {
+		for _, dependent := range dependentsOf(graph, name) {
+			if _, done := skipped[dependent]; !done {
+				skipped[dependent] = cause
+				skip(dependent, cause)
+			}
+		}
+	}
+
+	started := map[string]bool{}
+	results := make(chan graphResult)
+	errs := []error{}
+	running := 0
+
+	for len(ready) > 0 || running > 0 {
+		for len(ready) > 0 && running < limit && ctx.Err() == nil {
+			name := ready[0]
+			ready = ready[1:]
+
+			if _, done := skipped[name]; done {
+				continue
+			}
+
+			started[name] = true
+			running++
+
+			go func() {
+				if name == target {
+					results <- graphResult{name: name, err: run(ctx)}
+				} else {
+					results <- graphResult{name: name, err: i.execute(ctx, graph[name], nil)}
+				}
+			}()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+
+		if result.err != nil {
+			errs = append(errs, &TaskError{Task: result.name, Err: result.err})
+			skip(result.name, fmt.Sprintf("dependency %s failed", result.name))
+			continue
+		}
+
+		newlyReady := []string{}
+
+		for _, dependent := range dependentsOf(graph, result.name) {
+			remaining[dependent]--
+
+			if remaining[dependent] == 0 {
+				newlyReady = append(newlyReady, dependent)
+			}
+		}
+
+		ready = append(ready, newlyReady...)
+	}
+
+	stalled := []string{}
+
+	for name := range graph {
+		_, done := skipped[name]
+
+		if !done && !started[name] {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				skipped[name] = ctxErr.Error()
+			} else {
+				stalled = append(stalled, name)
+			}
+		}
+	}
+
+	if len(stalled) > 0 {
+		errs = append(errs, stalledError(stalled))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(skipped)) {
+		errs = append(errs, &SkippedError{Task: name, Cause: skipped[name]})
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil && len(errs) == 0 {
+		return ctxErr
+	}
+
+	return errors.Join(errs...)
+}
+
+func (i *Invoker) execute(ctx context.Context, task *Task, args []string) error {
+	return runCommand(ctx, task.command(i.out(), i.invoke), args)
+}
+
+// runCommand executes cmd with args. Nil args run it without arguments since
+// cobra would otherwise parse the process arguments.
+func runCommand(ctx context.Context, cmd *cobra.Command, args []string) error {
+	if args == nil {
+		args = []string{}
+	}
+
+	cmd.SetArgs(args)
+
+	return cmd.ExecuteContext(ctx)
+}
@@ -0,0 +1,153 @@
+package task_invoker_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Task dependencies", func() {
+	var (
+		out *bytes.Buffer
+		mu  sync.Mutex
+		ran []string
+	)
+
+	record := func(name string, err error) task_invoker.TaskFN {
+		return func(tc *task_invoker.TaskContext) error {
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			ran = append(ran, name)
+			mu.Unlock()
+
+			return err
+		}
+	}
+
+	newInvoker := func(name string, failing ...string) *task_invoker.Invoker {
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+
+		invoker := task_invoker.NewInvoker(name, cmd, nil)
+		invoker.Parallelism = 2
+
+		errFor := func(task string) error {
+			for _, f := range failing {
+				if f == task {
+					return errors.New(task + " failed")
+				}
+			}
+			return nil
+		}
+
+		invoker.AddTask(task_invoker.Task{Name: "schema", Run: record("schema", errFor("schema"))})
+		invoker.AddTask(task_invoker.Task{Name: "currencies", Run: record("currencies", errFor("currencies"))})
+		invoker.AddTask(task_invoker.Task{Name: "backfill", DependsOn: []string{"schema"}, Run: record("backfill", errFor("backfill"))})
+		invoker.AddTask(task_invoker.Task{Name: "deploy", DependsOn: []string{"backfill", "currencies"}, Run: record("deploy", errFor("deploy"))})
+
+		return invoker
+	}
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		ran = nil
+	})
+
+	It("resolves the execution plan", func() {
+		stages, err := newInvoker("deploy").Plan("deploy")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stages).To(Equal([][]string{{"currencies", "schema"}, {"backfill"}, {"deploy"}}))
+	})
+
+	It("runs the dependencies first", func(ctx SpecContext) {
+		Expect(newInvoker("deploy").RunContext(ctx)).To(Succeed())
+
+		Expect(ran).To(HaveLen(4))
+		Expect(ran[2:]).To(Equal([]string{"backfill", "deploy"}))
+	})
+
+	It("skips the downstream tasks when one fails", func(ctx SpecContext) {
+		err := newInvoker("deploy", "schema").RunContext(ctx)
+
+		Expect(err).To(MatchError(ContainSubstring("task schema: schema failed")))
+		Expect(err).To(MatchError(ContainSubstring("task backfill skipped: dependency schema failed")))
+		Expect(err).To(MatchError(ContainSubstring("task deploy skipped: dependency schema failed")))
+		Expect(ran).To(ConsistOf("schema", "currencies"))
+
+		var taskErr *task_invoker.TaskError
+		Expect(errors.As(err, &taskErr)).To(BeTrue())
+		Expect(taskErr.Task).To(Equal("schema"))
+	})
+
+	It("runs the dependencies without the process arguments", func(ctx SpecContext) {
+		setProcessArgs("report", "--extra", "foo")
+
+		invoker := newInvoker("report")
+		invoker.AddTask(task_invoker.Task{
+			Name:  "export",
+			Args:  []task_invoker.TaskArg{{Name: "format", Optional: true}},
+			Flags: func(flags *pflag.FlagSet) { flags.Bool("full", false, "export every row") },
+			Run: func(tc *task_invoker.TaskContext) error {
+				Expect(tc.Args).To(BeEmpty())
+				return record("export", nil)(tc)
+			},
+		})
+		invoker.AddTask(task_invoker.Task{Name: "report", DependsOn: []string{"export"}, Run: record("report", nil)})
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(ran).To(Equal([]string{"export", "report"}))
+	})
+
+	It("counts repeated dependencies once", func(ctx SpecContext) {
+		invoker := newInvoker("report")
+		invoker.AddTask(task_invoker.Task{Name: "report", DependsOn: []string{"schema", "schema"}, Run: record("report", nil)})
+
+		stages, err := invoker.Plan("report")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stages).To(Equal([][]string{{"schema"}, {"report"}}))
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(ran).To(Equal([]string{"schema", "report"}))
+	})
+
+	It("detects cycles", func(ctx SpecContext) {
+		invoker := newInvoker("a")
+		invoker.AddTask(task_invoker.Task{Name: "a", DependsOn: []string{"b"}, Run: record("a", nil)})
+		invoker.AddTask(task_invoker.Task{Name: "b", DependsOn: []string{"c"}, Run: record("b", nil)})
+		invoker.AddTask(task_invoker.Task{Name: "c", DependsOn: []string{"a"}, Run: record("c", nil)})
+
+		err := invoker.RunContext(ctx)
+
+		var cycle *task_invoker.CycleError
+		Expect(errors.As(err, &cycle)).To(BeTrue())
+		Expect(cycle.Cycle).To(Equal([]string{"a", "b", "c", "a"}))
+		Expect(ran).To(BeEmpty())
+	})
+
+	It("fails on unknown dependencies", func() {
+		invoker := newInvoker("x")
+		invoker.AddTask(task_invoker.Task{Name: "x", DependsOn: []string{"schem"}, Run: record("x", nil)})
+
+		_, err := invoker.Plan("x")
+		Expect(err).To(MatchError("task name schem is not registered, did you mean: schema?"))
+	})
+
+	It("prints the plan in dry-run mode", func(ctx SpecContext) {
+		invoker := newInvoker("deploy")
+		invoker.DryRun = true
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(ran).To(BeEmpty())
+		Expect(out.String()).To(Equal("Execution plan for deploy:\n  1. currencies, schema\n  2. backfill\n  3. deploy\n"))
+	})
+})
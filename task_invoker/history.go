@@ -0,0 +1,516 @@
+package task_invoker
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/internal/sql_utils"
+	"github.com/Nuanu-com/go-utils/pagination"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+type RunOutcome string
+
+const (
+	OutcomeSucceeded RunOutcome = "succeeded"
+	OutcomeFailed    RunOutcome = "failed"
+	OutcomeCancelled RunOutcome = "cancelled"
+	OutcomeTimedOut  RunOutcome = "timed_out"
+	// OutcomeLocked means the task did not run because another run held the
+	// lock.
+	OutcomeLocked RunOutcome = "locked"
+)
+
+type RunRecord struct {
+	ID         string        `json:"id"`
+	Task       string        `json:"task"`
+	Args       []string      `json:"args"`
+	StartedAt  time.Time     `json:"started_at"`
+	EndedAt    time.Time     `json:"ended_at"`
+	Duration   time.Duration `json:"duration"`
+	Attempts   int           `json:"attempts"`
+	Outcome    RunOutcome    `json:"outcome"`
+	Error      string        `json:"error,omitempty"`
+	ErrorChain []string      `json:"error_chain,omitempty"`
+	Result     any           `json:"result,omitempty"`
+}
+
+type Recorder interface {
+	Record(ctx context.Context, record RunRecord) error
+}
+
+type HistoryQuery struct {
+	Task    string
+	Outcome RunOutcome
+	Since   time.Time
+	// Limit keeps the most recent records. Zero returns every record.
+	Limit int
+}
+
+// HistoryReader is implemented by the recorders that can be queried. Records
+// are returned most recent first.
+type HistoryReader interface {
+	History(ctx context.Context, query HistoryQuery) ([]RunRecord, error)
+}
+
+func (q HistoryQuery) matches(record RunRecord) bool {
+	return (q.Task == "" || q.Task == record.Task) &&
+		(q.Outcome == "" || q.Outcome == record.Outcome) &&
+		(q.Since.IsZero() || !record.StartedAt.Before(q.Since))
+}
+
+func (q HistoryQuery) apply(records []RunRecord) []RunRecord {
+	results := []RunRecord{}
+
+	for _, record := range records {
+		if q.matches(record) {
+			results = append(results, record)
+		}
+	}
+
+	slices.SortStableFunc(results, func(a, b RunRecord) int { return b.StartedAt.Compare(a.StartedAt) })
+
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+
+	return results
+}
+
+func (i *Invoker) record(tc *TaskContext, startedAt time.Time, attempts int, err error) {
+	if len(i.Recorders) == 0 {
+		return
+	}
+
+	endedAt := time.Now()
+	record := RunRecord{
+		ID:        uuid.NewString(),
+		Task:      tc.Name,
+		Args:      tc.Args,
+		StartedAt: startedAt,
+		EndedAt:   endedAt,
+		Duration:  endedAt.Sub(startedAt),
+		Attempts:  attempts,
+		Outcome:   outcomeOf(err),
+		Result:    tc.result.value,
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+		record.ErrorChain = errorChain(err)
+	}
+
+	// The task context may be cancelled already; the record must still land.
+	ctx := context.WithoutCancel(tc)
+
+	for _, recorder := range i.Recorders {
+		if recordErr := recorder.Record(ctx, record); recordErr != nil {
+			i.logger().Error("failed to record task run", slog.String("task", tc.Name), slog.Any("error", recordErr))
+		}
+	}
+}
+
+func outcomeOf(err error) RunOutcome {
+	if errors.Is(err, ErrLocked) {
+		return OutcomeLocked
+	}
+
+	switch ExitCode(err) {
+	case ExitSuccess:
+		return OutcomeSucceeded
+	case ExitTimeout:
+		return OutcomeTimedOut
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return OutcomeCancelled
+	}
+
+	return OutcomeFailed
+}
+
+// errorChain flattens the wrapped errors, including errors.Join trees, depth
+// first.
+func errorChain(err error) []string {
+	results := []string{}
+	queue := []error{err}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == nil {
+			continue
+		}
+
+		results = append(results, current.Error())
+
+		switch unwrapper := current.(type) {
+		case interface{ Unwrap() error }:
+			queue = append([]error{unwrapper.Unwrap()}, queue...)
+		case interface{ Unwrap() []error }:
+			queue = append(slices.Clone(unwrapper.Unwrap()), queue...)
+		}
+	}
+
+	return results
+}
+
+// SlogRecorder logs every run as a structured record.
+type SlogRecorder struct {
+	Logger *slog.Logger
+}
+
+func (r *SlogRecorder) Record(ctx context.Context, record RunRecord) error {
+	level := slog.LevelInfo
+
+	if record.Outcome != OutcomeSucceeded {
+		level = slog.LevelError
+	}
+
+	r.Logger.LogAttrs(ctx, level, "task run",
+		slog.String("run_id", record.ID),
+		slog.String("task", record.Task),
+		slog.Any("args", record.Args),
+		slog.Time("started_at", record.StartedAt),
+		slog.Time("ended_at", record.EndedAt),
+		slog.Duration("duration", record.Duration),
+		slog.Int("attempts", record.Attempts),
+		slog.String("outcome", string(record.Outcome)),
+		slog.String("error", record.Error),
+		slog.Any("error_chain", record.ErrorChain),
+		slog.Any("result", record.Result),
+	)
+
+	return nil
+}
+
+type MemoryRecorder struct {
+	mu      sync.Mutex
+	records []RunRecord
+}
+
+func NewMemoryRecorder() *MemoryRecorder {
+	return &MemoryRecorder{}
+}
+
+func (r *MemoryRecorder) Record(ctx context.Context, record RunRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, record)
+
+	return nil
+}
+
+func (r *MemoryRecorder) History(ctx context.Context, query HistoryQuery) ([]RunRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return query.apply(r.records), nil
+}
+
+// JSONLinesRecorder appends one JSON document per run to a file.
+type JSONLinesRecorder struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewJSONLinesRecorder(path string) *JSONLinesRecorder {
+	return &JSONLinesRecorder{Path: path}
+}
+
+func (r *JSONLinesRecorder) Record(ctx context.Context, record RunRecord) error {
+	data, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	file, err := os.OpenFile(r.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(data, '\n'))
+
+	return errors.Join(err, file.Close())
+}
+
+func (r *JSONLinesRecorder) History(ctx context.Context, query HistoryQuery) ([]RunRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	file, err := os.Open(r.Path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return []RunRecord{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	records := []RunRecord{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+
+	for scanner.Scan() {
+		var record RunRecord
+
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return query.apply(records), nil
+}
+
+// DB is the subset of *sql.DB, *sql.Conn and *sql.Tx used by SQLRecorder.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// SQLRecorder stores runs in a table shaped like:
+//
+//	CREATE TABLE task_runs (
+//		id          TEXT PRIMARY KEY,
+//		task        TEXT NOT NULL,
+//		args        TEXT NOT NULL,
+//		started_at  TIMESTAMP NOT NULL,
+//		ended_at    TIMESTAMP NOT NULL,
+//		duration_ns BIGINT NOT NULL,
+//		attempts    INTEGER NOT NULL,
+//		outcome     TEXT NOT NULL,
+//		error       TEXT NOT NULL,
+//		error_chain TEXT NOT NULL,
+//		result      TEXT NOT NULL
+//	)
+//
+// args, error_chain and result hold JSON documents.
+type SQLRecorder struct {
+	DB DB
+	// Table is interpolated into the queries; NewSQLRecorder checks that it
+	// is a plain identifier.
+	Table       string
+	Placeholder pagination.Placeholder
+}
+
+const sqlRecorderColumns = "id, task, args, started_at, ended_at, duration_ns, attempts, outcome, error, error_chain, result"
+
+// NewSQLRecorder fails on a table that is not a plain identifier, since it is
+// interpolated into the queries.
+func NewSQLRecorder(db DB, table string, placeholder pagination.Placeholder) (*SQLRecorder, error) {
+	if !sql_utils.IsIdentifier(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+
+	return &SQLRecorder{DB: db, Table: table, Placeholder: placeholder}, nil
+}
+
+func (r *SQLRecorder) Record(ctx context.Context, record RunRecord) error {
+	documents := []string{}
+
+	for _, value := range []any{record.Args, record.ErrorChain, record.Result} {
+		data, err := json.Marshal(value)
+
+		if err != nil {
+			return err
+		}
+
+		documents = append(documents, string(data))
+	}
+
+	values := []any{
+		record.ID, record.Task, documents[0], record.StartedAt, record.EndedAt, int64(record.Duration),
+		record.Attempts, string(record.Outcome), record.Error, documents[1], documents[2],
+	}
+
+	placeholders := make([]string, len(values))
+
+	for idx := range values {
+		placeholders[idx] = r.Placeholder(idx + 1)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", r.Table, sqlRecorderColumns, strings.Join(placeholders, ", "))
+	_, err := r.DB.ExecContext(ctx, query, values...)
+
+	return err
+}
+
+func (r *SQLRecorder) History(ctx context.Context, query HistoryQuery) ([]RunRecord, error) {
+	conditions := []string{}
+	args := []any{}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, r.Placeholder(len(args))))
+	}
+
+	if query.Task != "" {
+		add("task = %s", query.Task)
+	}
+
+	if query.Outcome != "" {
+		add("outcome = %s", string(query.Outcome))
+	}
+
+	if !query.Since.IsZero() {
+		add("started_at >= %s", query.Since)
+	}
+
+	statement := fmt.Sprintf("SELECT %s FROM %s", sqlRecorderColumns, r.Table)
+
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	statement += " ORDER BY started_at DESC"
+
+	if query.Limit > 0 {
+		statement += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, statement, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	records := []RunRecord{}
+
+	for rows.Next() {
+		var (
+			record                      RunRecord
+			argsJSON, chainJSON, result string
+			outcome                     string
+			duration                    int64
+		)
+
+		err := rows.Scan(
+			&record.ID, &record.Task, &argsJSON, &record.StartedAt, &record.EndedAt, &duration,
+			&record.Attempts, &outcome, &record.Error, &chainJSON, &result,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		record.Duration = time.Duration(duration)
+		record.Outcome = RunOutcome(outcome)
+
+		documents := []struct {
+			data   string
+			target any
+		}{
+			{argsJSON, &record.Args},
+			{chainJSON, &record.ErrorChain},
+			{result, &record.Result},
+		}
+
+		for _, document := range documents {
+			if err := json.Unmarshal([]byte(document.data), document.target); err != nil {
+				return nil, err
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// HistoryCommand returns the "history" subcommand printing the most recent
+// runs of reader, with its own --json flag. BuildCommand already adds it,
+// sharing the root --json flag, when a recorder of the invoker reads history.
+func HistoryCommand(reader HistoryReader) *cobra.Command {
+	var asJSON bool
+
+	cmd := historyCommand(reader, func() bool { return asJSON })
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print the runs as JSON")
+
+	return cmd
+}
+
+func historyCommand(reader HistoryReader, asJSON func() bool) *cobra.Command {
+	var (
+		query   HistoryQuery
+		outcome string
+		since   time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show past task runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query.Outcome = RunOutcome(outcome)
+
+			if since > 0 {
+				query.Since = time.Now().Add(-since)
+			}
+
+			records, err := reader.History(cmd.Context(), query)
+
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+
+			if asJSON() {
+				return writeJSON(out, records)
+			}
+
+			writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "STARTED\tTASK\tOUTCOME\tDURATION\tATTEMPTS\tERROR")
+
+			for _, record := range records {
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%s\n",
+					record.StartedAt.Format(time.DateTime),
+					record.Task,
+					record.Outcome,
+					record.Duration.Round(time.Millisecond),
+					record.Attempts,
+					record.Error,
+				)
+			}
+
+			return writer.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&query.Task, "task", "", "only show runs of this task")
+	cmd.Flags().StringVar(&outcome, "outcome", "", "only show runs with this outcome (succeeded, failed, cancelled, timed_out, locked)")
+	cmd.Flags().DurationVar(&since, "since", 0, "only show runs started within this duration, e.g. 24h")
+	cmd.Flags().IntVar(&query.Limit, "limit", 20, "maximum number of runs to show, 0 for all")
+
+	return cmd
+}
@@ -0,0 +1,319 @@
+package task_invoker_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/pagination"
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Run history", func() {
+	var recorder *task_invoker.MemoryRecorder
+
+	newInvoker := func(task task_invoker.Task, args ...string) *task_invoker.Invoker {
+		invoker := task_invoker.NewInvoker(task.Name, &cobra.Command{}, args)
+		invoker.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+		invoker.Recorders = []task_invoker.Recorder{recorder}
+		invoker.AddTask(task)
+
+		return invoker
+	}
+
+	BeforeEach(func() {
+		recorder = task_invoker.NewMemoryRecorder()
+	})
+
+	It("records a successful run with its args and result", func(ctx SpecContext) {
+		invoker := newInvoker(task_invoker.Task{
+			Name: "import",
+			Args: []task_invoker.TaskArg{{Name: "file"}},
+			Run: func(tc *task_invoker.TaskContext) error {
+				tc.SetResult(map[string]int{"rows": 42})
+				return nil
+			},
+		}, "bookings.csv")
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+
+		records, err := recorder.History(ctx, task_invoker.HistoryQuery{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+
+		record := records[0]
+		Expect(record.ID).NotTo(BeEmpty())
+		Expect(record.Task).To(Equal("import"))
+		Expect(record.Args).To(Equal([]string{"bookings.csv"}))
+		Expect(record.Outcome).To(Equal(task_invoker.OutcomeSucceeded))
+		Expect(record.Attempts).To(Equal(1))
+		Expect(record.Result).To(Equal(map[string]int{"rows": 42}))
+		Expect(record.EndedAt).NotTo(BeTemporally("<", record.StartedAt))
+		Expect(record.Duration).To(Equal(record.EndedAt.Sub(record.StartedAt)))
+	})
+
+	It("records the error chain and attempts of a failed run", func(ctx SpecContext) {
+		invoker := newInvoker(task_invoker.Task{
+			Name:  "sync",
+			Retry: &task_invoker.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			Run: func(tc *task_invoker.TaskContext) error {
+				return fmt.Errorf("sync partner: %w", errors.New("connection refused"))
+			},
+		})
+
+		Expect(invoker.RunContext(ctx)).NotTo(Succeed())
+
+		records, _ := recorder.History(ctx, task_invoker.HistoryQuery{})
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Outcome).To(Equal(task_invoker.OutcomeFailed))
+		Expect(records[0].Attempts).To(Equal(2))
+		Expect(records[0].Error).To(Equal("sync partner: connection refused"))
+		Expect(records[0].ErrorChain).To(Equal([]string{"sync partner: connection refused", "connection refused"}))
+	})
+
+	It("records timeouts", func(ctx SpecContext) {
+		invoker := newInvoker(task_invoker.Task{
+			Name:    "slow",
+			Timeout: time.Millisecond,
+			Run: func(tc *task_invoker.TaskContext) error {
+				<-tc.Done()
+				return tc.Err()
+			},
+		})
+
+		Expect(invoker.RunContext(ctx)).To(MatchError(context.DeadlineExceeded))
+
+		records, _ := recorder.History(ctx, task_invoker.HistoryQuery{})
+		Expect(records[0].Outcome).To(Equal(task_invoker.OutcomeTimedOut))
+	})
+
+	It("filters and orders the history", func(ctx SpecContext) {
+		start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+		for idx, task := range []string{"a", "b", "a", "a"} {
+			outcome := task_invoker.OutcomeSucceeded
+
+			if idx == 2 {
+				outcome = task_invoker.OutcomeFailed
+			}
+
+			Expect(recorder.Record(ctx, task_invoker.RunRecord{
+				ID:        fmt.Sprint(idx),
+				Task:      task,
+				StartedAt: start.Add(time.Duration(idx) * time.Hour),
+				Outcome:   outcome,
+			})).To(Succeed())
+		}
+
+		ids := func(query task_invoker.HistoryQuery) []string {
+			records, err := recorder.History(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+
+			results := []string{}
+
+			for _, record := range records {
+				results = append(results, record.ID)
+			}
+
+			return results
+		}
+
+		Expect(ids(task_invoker.HistoryQuery{})).To(Equal([]string{"3", "2", "1", "0"}))
+		Expect(ids(task_invoker.HistoryQuery{Task: "a"})).To(Equal([]string{"3", "2", "0"}))
+		Expect(ids(task_invoker.HistoryQuery{Task: "a", Limit: 1})).To(Equal([]string{"3"}))
+		Expect(ids(task_invoker.HistoryQuery{Outcome: task_invoker.OutcomeFailed})).To(Equal([]string{"2"}))
+		Expect(ids(task_invoker.HistoryQuery{Since: start.Add(time.Hour)})).To(Equal([]string{"3", "2", "1"}))
+	})
+
+	It("appends runs to a JSON lines file", func(ctx SpecContext) {
+		jsonl := task_invoker.NewJSONLinesRecorder(filepath.Join(GinkgoT().TempDir(), "runs.jsonl"))
+
+		records, err := jsonl.History(ctx, task_invoker.HistoryQuery{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(BeEmpty())
+
+		invoker := newInvoker(task_invoker.Task{
+			Name: "report",
+			Run: func(tc *task_invoker.TaskContext) error {
+				tc.SetResult("sent")
+				return nil
+			},
+		})
+		invoker.Recorders = []task_invoker.Recorder{jsonl}
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+
+		records, err = jsonl.History(ctx, task_invoker.HistoryQuery{Task: "report"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+		Expect(records[0].Result).To(Equal("sent"))
+		Expect(records[0].Outcome).To(Equal(task_invoker.OutcomeSucceeded))
+	})
+
+	It("stores runs in a SQL table", func(ctx SpecContext) {
+		sqlRecorder, err := task_invoker.NewSQLRecorder(openSQLite(`CREATE TABLE task_runs (
+			id          TEXT PRIMARY KEY,
+			task        TEXT NOT NULL,
+			args        TEXT NOT NULL,
+			started_at  TIMESTAMP NOT NULL,
+			ended_at    TIMESTAMP NOT NULL,
+			duration_ns BIGINT NOT NULL,
+			attempts    INTEGER NOT NULL,
+			outcome     TEXT NOT NULL,
+			error       TEXT NOT NULL,
+			error_chain TEXT NOT NULL,
+			result      TEXT NOT NULL
+		)`), "task_runs", pagination.QuestionPlaceholder)
+		Expect(err).NotTo(HaveOccurred())
+
+		invoker := newInvoker(task_invoker.Task{
+			Name: "import",
+			Args: []task_invoker.TaskArg{{Name: "file"}},
+			Run: func(tc *task_invoker.TaskContext) error {
+				if tc.Arg("file") == "broken.csv" {
+					return fmt.Errorf("import: %w", errors.New("bad header"))
+				}
+
+				tc.SetResult(map[string]int{"rows": 42})
+				return nil
+			},
+		}, "bookings.csv")
+		invoker.Recorders = []task_invoker.Recorder{sqlRecorder}
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+
+		invoker.Args = []string{"broken.csv"}
+		Expect(invoker.RunContext(ctx)).NotTo(Succeed())
+
+		records, err := sqlRecorder.History(ctx, task_invoker.HistoryQuery{Task: "import"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+
+		failed := records[0]
+		Expect(failed.Args).To(Equal([]string{"broken.csv"}))
+		Expect(failed.Outcome).To(Equal(task_invoker.OutcomeFailed))
+		Expect(failed.ErrorChain).To(Equal([]string{"import: bad header", "bad header"}))
+
+		succeeded := records[1]
+		Expect(succeeded.Outcome).To(Equal(task_invoker.OutcomeSucceeded))
+		Expect(succeeded.Attempts).To(Equal(1))
+		Expect(succeeded.Result).To(Equal(map[string]any{"rows": 42.0}))
+		Expect(succeeded.Duration).To(BeNumerically(">", 0))
+
+		latest, err := sqlRecorder.History(ctx, task_invoker.HistoryQuery{Outcome: task_invoker.OutcomeSucceeded, Limit: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(latest).To(HaveLen(1))
+		Expect(latest[0].ID).To(Equal(succeeded.ID))
+
+		records, err = sqlRecorder.History(ctx, task_invoker.HistoryQuery{Since: failed.StartedAt})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].ID).To(Equal(failed.ID))
+	})
+
+	It("rejects SQL table names that are not identifiers", func() {
+		_, err := task_invoker.NewSQLRecorder(nil, "task_runs; DROP TABLE task_runs", pagination.QuestionPlaceholder)
+		Expect(err).To(MatchError(`invalid table name "task_runs; DROP TABLE task_runs"`))
+	})
+
+	It("logs runs with the slog recorder", func(ctx SpecContext) {
+		logs := &bytes.Buffer{}
+		invoker := newInvoker(task_invoker.Task{
+			Name: "cleanup",
+			Run:  func(*task_invoker.TaskContext) error { return errors.New("disk full") },
+		})
+		invoker.Recorders = []task_invoker.Recorder{
+			&task_invoker.SlogRecorder{Logger: slog.New(slog.NewJSONHandler(logs, nil))},
+		}
+
+		Expect(invoker.RunContext(ctx)).NotTo(Succeed())
+
+		var entry map[string]any
+		Expect(json.Unmarshal(logs.Bytes(), &entry)).To(Succeed())
+		Expect(entry).To(HaveKeyWithValue("level", "ERROR"))
+		Expect(entry).To(HaveKeyWithValue("task", "cleanup"))
+		Expect(entry).To(HaveKeyWithValue("outcome", "failed"))
+		Expect(entry).To(HaveKeyWithValue("error", "disk full"))
+	})
+
+	It("prints the history subcommand", func(ctx SpecContext) {
+		Expect(recorder.Record(ctx, task_invoker.RunRecord{
+			Task:      "import",
+			StartedAt: time.Now(),
+			Duration:  1500 * time.Millisecond,
+			Attempts:  1,
+			Outcome:   task_invoker.OutcomeSucceeded,
+		})).To(Succeed())
+		Expect(recorder.Record(ctx, task_invoker.RunRecord{
+			Task:      "sync",
+			StartedAt: time.Now(),
+			Attempts:  3,
+			Outcome:   task_invoker.OutcomeFailed,
+			Error:     "connection refused",
+		})).To(Succeed())
+
+		out := &bytes.Buffer{}
+		cmd := task_invoker.HistoryCommand(recorder)
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"--task", "import"})
+
+		Expect(cmd.ExecuteContext(ctx)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("OUTCOME"))
+		Expect(out.String()).To(ContainSubstring("import"))
+		Expect(out.String()).To(ContainSubstring("1.5s"))
+		Expect(out.String()).NotTo(ContainSubstring("sync"))
+
+		out.Reset()
+		cmd = task_invoker.HistoryCommand(recorder)
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"--outcome", "failed", "--json"})
+
+		Expect(cmd.ExecuteContext(ctx)).To(Succeed())
+
+		var records []task_invoker.RunRecord
+		Expect(json.Unmarshal(out.Bytes(), &records)).To(Succeed())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Error).To(Equal("connection refused"))
+	})
+
+	It("adds the history subcommand to BuildCommand with the root --json flag", func(ctx SpecContext) {
+		invoker := newInvoker(task_invoker.Task{
+			Name: "import",
+			Run:  func(*task_invoker.TaskContext) error { return nil },
+		})
+		invoker.Signals = nil
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+
+		out := &bytes.Buffer{}
+		cmd := task_invoker.BuildCommand(invoker)
+		cmd.SetOut(out)
+
+		for _, args := range [][]string{{"history", "--json"}, {"--json", "history"}} {
+			out.Reset()
+			cmd.SetArgs(args)
+
+			Expect(cmd.ExecuteContext(ctx)).To(Succeed())
+
+			history, _, err := cmd.Find([]string{"history"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history.LocalFlags().Lookup("json")).To(BeNil())
+
+			var records []task_invoker.RunRecord
+			Expect(json.Unmarshal(out.Bytes(), &records)).To(Succeed())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].Task).To(Equal("import"))
+		}
+	})
+})
@@ -1,19 +1,67 @@
 package task_invoker
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 type InvokerFN func() error
+type InvokerCtxFN func(ctx context.Context) error
+
+// Deprecated: InvokerList is no longer used by Invoker. Register tasks with
+// Add or AddTask instead.
 type InvokerList map[string]InvokerFN
 
 type Invoker struct {
-	name        string
-	invokerList InvokerList
-	Cmd         *cobra.Command
-	Args        []string
+	name  string
+	tasks map[string]*Task
+	Cmd   *cobra.Command
+	// Args are parsed by the task. Nil runs it without arguments rather than
+	// with the process arguments.
+	Args []string
+	// Signals cancel the task context when received. Runs involving a task
+	// registered with Add, which cannot be cancelled, keep the default
+	// signal handling. Nil disables the signal handling.
+	Signals []os.Signal
+	// GracePeriod is how long a cancelled task may take to return before Run
+	// gives up on it.
+	GracePeriod time.Duration
+	// Parallelism caps how many independent dependencies run at once.
+	Parallelism int
+	// DryRun prints the execution plan instead of running the tasks.
+	DryRun bool
+	// Locks prevents a task from running twice at once. Nil disables locking.
+	Locks LockStore
+	// Logger receives a structured record of every attempt. Nil, the
+	// default, discards them.
+	Logger *slog.Logger
+	// Recorders receive a RunRecord after every task run.
+	Recorders  []Recorder
+	middleware []Middleware
+}
+
+type UnknownTaskError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownTaskError) Error() string {
+	msg := fmt.Sprintf("task name %s is not registered", e.Name)
+
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(", did you mean: %s?", strings.Join(e.Suggestions, ", "))
+	}
+
+	return msg
 }
 
 func NewInvoker(
@@ -25,20 +73,186 @@ func NewInvoker(
 		name:        name,
 		Cmd:         cmd,
 		Args:        args,
-		invokerList: InvokerList{},
+		tasks:       map[string]*Task{},
+		Signals:     []os.Signal{os.Interrupt, syscall.SIGTERM},
+		GracePeriod: 10 * time.Second,
+		Parallelism: 1,
 	}
 }
 
 func (i *Invoker) Add(name string, fn InvokerFN) {
-	i.invokerList[name] = fn
+	i.AddTask(Task{
+		Name:           name,
+		Run:            func(*TaskContext) error { return fn() },
+		ignoresContext: true,
+	})
 }
 
-func (i *Invoker) Run() error {
-	invoker, found := i.invokerList[i.name]
+func (i *Invoker) AddContext(name string, fn InvokerCtxFN) {
+	i.AddTask(Task{
+		Name: name,
+		Run:  func(tc *TaskContext) error { return fn(tc) },
+	})
+}
+
+// AddTask registers a task, replacing any task with the same name.
+func (i *Invoker) AddTask(task Task) {
+	i.tasks[task.Name] = &task
+}
+
+// Task returns the registered task, or an *UnknownTaskError suggesting close
+// matches.
+func (i *Invoker) Task(name string) (*Task, error) {
+	task, found := i.tasks[name]
 
 	if !found {
-		return fmt.Errorf("task name %s is not registered", i.name)
+		return nil, &UnknownTaskError{Name: name, Suggestions: suggest(name, i.TaskNames())}
+	}
+
+	return task, nil
+}
+
+func (i *Invoker) TaskNames() []string {
+	names := make([]string, 0, len(i.tasks))
+
+	for name := range i.tasks {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Run invokes the task selected in NewInvoker with Args, using the context of
+// Cmd when it has one. Unless registered as tasks, "list" prints every task
+// and "help [task]" prints the task usage. Use ExitCode to turn the returned
+// error into a process exit code.
+func (i *Invoker) Run() error {
+	ctx := context.Background()
+
+	if i.Cmd != nil && i.Cmd.Context() != nil {
+		ctx = i.Cmd.Context()
+	}
+
+	return i.RunContext(ctx)
+}
+
+func (i *Invoker) RunContext(ctx context.Context) error {
+	if _, found := i.tasks[i.name]; !found {
+		switch i.name {
+		case "list":
+			return i.PrintList(i.out())
+		case "help":
+			return i.printHelp()
+		}
+	}
+
+	if _, err := i.Task(i.name); err != nil {
+		return err
+	}
+
+	if i.DryRun {
+		return i.PrintPlan(i.out(), i.name)
+	}
+
+	return i.withSignals(ctx, i.name, func(ctx context.Context) error {
+		return i.runTask(ctx, i.name, i.Args)
+	})
+}
+
+// runTask runs the named task, after its dependencies when it has any.
+func (i *Invoker) runTask(ctx context.Context, name string, args []string) error {
+	task, err := i.Task(name)
+
+	if err != nil {
+		return err
+	}
+
+	if len(task.DependsOn) > 0 {
+		return i.runGraph(ctx, task.Name, func(ctx context.Context) error {
+			return i.execute(ctx, task, args)
+		})
+	}
+
+	return i.execute(ctx, task, args)
+}
+
+func (i *Invoker) PrintList(out io.Writer) error {
+	fmt.Fprintln(out, "Available tasks:")
+
+	width := 0
+
+	for _, name := range i.TaskNames() {
+		width = max(width, len(name))
+	}
+
+	for _, name := range i.TaskNames() {
+		fmt.Fprintf(out, "  %-*s  %s\n", width, name, i.tasks[name].Description)
+	}
+
+	return nil
+}
+
+func (i *Invoker) printHelp() error {
+	if len(i.Args) == 0 {
+		return i.PrintList(i.out())
+	}
+
+	task, err := i.Task(i.Args[0])
+
+	if err != nil {
+		return err
+	}
+
+	return task.command(i.out(), i.invoke).Help()
+}
+
+func (i *Invoker) out() io.Writer {
+	if i.Cmd != nil {
+		return i.Cmd.OutOrStdout()
+	}
+
+	return os.Stdout
+}
+
+func suggest(name string, candidates []string) []string {
+	results := []string{}
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, name) || levenshtein(strings.ToLower(name), strings.ToLower(candidate)) <= 2 {
+			results = append(results, candidate)
+		}
+	}
+
+	return results
+}
+
+// levenshtein counts the rune edits turning a into b.
+func levenshtein(aText string, bText string) int {
+	a, b := []rune(aText), []rune(bText)
+	prev := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(prev[j]+1, current[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, current = current, prev
 	}
 
-	return invoker()
+	return prev[len(b)]
 }
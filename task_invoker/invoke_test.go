@@ -0,0 +1,118 @@
+package task_invoker_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invoker", func() {
+	var out *bytes.Buffer
+
+	newInvoker := func(name string, args ...string) *task_invoker.Invoker {
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+
+		invoker := task_invoker.NewInvoker(name, cmd, args)
+		invoker.Add("legacy", func() error { return errors.New("legacy ran") })
+		invoker.AddTask(task_invoker.Task{
+			Name:        "settle",
+			Description: "Settles the bank statements",
+			Args: []task_invoker.TaskArg{
+				{Name: "bank", Description: "bank code"},
+				{Name: "date", Description: "settlement date", Optional: true},
+			},
+			Flags: func(flags *pflag.FlagSet) {
+				flags.Int("batch", 100, "batch size")
+				flags.Bool("dry", false, "do not write")
+			},
+			Run: func(tc *task_invoker.TaskContext) error {
+				batch, err := tc.Flags.GetInt("batch")
+				if err != nil {
+					return err
+				}
+
+				dry, _ := tc.Flags.GetBool("dry")
+				_, err = fmt.Fprintf(tc.Out, "%s|%s|%t|%d", tc.Arg("bank"), tc.Arg("date"), dry, batch)
+				return err
+			},
+		})
+
+		return invoker
+	}
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+	})
+
+	It("runs the legacy tasks", func() {
+		Expect(newInvoker("legacy", "--anything").Run()).To(MatchError("legacy ran"))
+	})
+
+	It("parses the arguments and flags", func() {
+		Expect(newInvoker("settle", "bca", "2025-07-01", "--batch", "300", "--dry").Run()).To(Succeed())
+		Expect(out.String()).To(Equal("bca|2025-07-01|true|300"))
+	})
+
+	It("uses the flag defaults and optional arguments", func() {
+		Expect(newInvoker("settle", "bca").Run()).To(Succeed())
+		Expect(out.String()).To(Equal("bca||false|100"))
+	})
+
+	It("validates the arguments", func() {
+		Expect(newInvoker("settle").Run()).To(MatchError(ContainSubstring("accepts between 1 and 2 arg(s)")))
+		Expect(newInvoker("settle", "bca", "--unknown").Run()).To(MatchError(ContainSubstring("unknown flag")))
+	})
+
+	It("runs the task without arguments when Args is nil", func() {
+		setProcessArgs("solo")
+
+		invoker := task_invoker.NewInvoker("solo", &cobra.Command{}, nil)
+		invoker.Cmd.SetOut(out)
+		invoker.AddTask(task_invoker.Task{
+			Name: "solo",
+			Args: []task_invoker.TaskArg{{Name: "mode", Optional: true}},
+			Run: func(tc *task_invoker.TaskContext) error {
+				_, err := fmt.Fprintf(tc.Out, "solo args: %v", tc.Args)
+				return err
+			},
+		})
+
+		Expect(invoker.Run()).To(Succeed())
+		Expect(out.String()).To(Equal("solo args: []"))
+	})
+
+	It("suggests close matches for unknown tasks", func() {
+		err := newInvoker("setle").Run()
+
+		var unknown *task_invoker.UnknownTaskError
+		Expect(errors.As(err, &unknown)).To(BeTrue())
+		Expect(unknown.Suggestions).To(Equal([]string{"settle"}))
+		Expect(err).To(MatchError("task name setle is not registered, did you mean: settle?"))
+
+		Expect(newInvoker("unrelated").Run()).To(MatchError("task name unrelated is not registered"))
+
+		invoker := task_invoker.NewInvoker("cafe", &cobra.Command{}, nil)
+		invoker.Add("cafés", func() error { return nil })
+		Expect(invoker.Run()).To(MatchError("task name cafe is not registered, did you mean: cafés?"))
+	})
+
+	It("lists the tasks", func() {
+		Expect(newInvoker("list").Run()).To(Succeed())
+		Expect(out.String()).To(Equal("Available tasks:\n  legacy  \n  settle  Settles the bank statements\n"))
+	})
+
+	It("prints the task help", func() {
+		Expect(newInvoker("help", "settle").Run()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("settle [flags] <bank> [date]"))
+		Expect(out.String()).To(ContainSubstring("settlement date"))
+		Expect(out.String()).To(ContainSubstring("--batch int"))
+	})
+})
@@ -0,0 +1,201 @@
+package task_invoker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultLockTTL = time.Hour
+
+var ErrLocked = errors.New("task is already running")
+
+// LockStore guards tasks against concurrent runs. Acquire fails with
+// ErrLocked while another holder owns an unexpired lock on key.
+type LockStore interface {
+	Acquire(ctx context.Context, key string, ttl time.Duration) (release func() error, err error)
+}
+
+type MemoryLockStore struct {
+	mu    sync.Mutex
+	locks map[string]time.Time
+}
+
+func NewMemoryLockStore() *MemoryLockStore {
+	return &MemoryLockStore{locks: map[string]time.Time{}}
+}
+
+func (s *MemoryLockStore) Acquire(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expiresAt, found := s.locks[key]; found && time.Now().Before(expiresAt) {
+		return nil, fmt.Errorf("%w: %s", ErrLocked, key)
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	s.locks[key] = expiresAt
+
+	return func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.locks[key].Equal(expiresAt) {
+			delete(s.locks, key)
+		}
+
+		return nil
+	}, nil
+}
+
+// FileLockStore holds locks as files in a directory, so processes sharing
+// the directory exclude each other. Stale locks past their TTL are taken
+// over. Keys are escaped into file names, so they cannot point outside Dir.
+type FileLockStore struct {
+	Dir string
+}
+
+type fileLock struct {
+	PID       int       `json:"pid"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// brokenLockAge is how old a lock file with unreadable content, e.g. left
+// half written by a crash, must be to be taken over.
+const brokenLockAge = time.Minute
+
+func NewFileLockStore(dir string) (*FileLockStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileLockStore{Dir: dir}, nil
+}
+
+func (s *FileLockStore) Acquire(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	path := filepath.Join(s.Dir, url.PathEscape(key)+".lock")
+	content, err := json.Marshal(fileLock{PID: os.Getpid(), ExpiresAt: time.Now().Add(ttl)})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for range 2 {
+		err := s.create(path, content)
+
+		if err == nil {
+			return func() error {
+				current, err := os.ReadFile(path)
+
+				if err != nil || !bytes.Equal(current, content) {
+					return nil
+				}
+
+				return os.Remove(path)
+			}, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		current, stale := s.stale(path)
+
+		if !stale {
+			return nil, fmt.Errorf("%w: %s", ErrLocked, key)
+		}
+
+		taken, err := s.takeOver(path, current)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !taken {
+			return nil, fmt.Errorf("%w: %s", ErrLocked, key)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrLocked, key)
+}
+
+// create writes content to a temporary file and links it to path, so the
+// lock never exists without its content. It fails with os.ErrExist when path
+// exists.
+func (s *FileLockStore) create(path string, content []byte) error {
+	file, err := os.CreateTemp(s.Dir, ".lock-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(content)
+
+	if err := errors.Join(err, file.Close()); err != nil {
+		return err
+	}
+
+	return os.Link(file.Name(), path)
+}
+
+// stale returns the content of the lock at path and whether it can be taken
+// over.
+func (s *FileLockStore) stale(path string) ([]byte, bool) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, errors.Is(err, os.ErrNotExist)
+	}
+
+	var lock fileLock
+
+	if err := json.Unmarshal(data, &lock); err != nil {
+		info, err := os.Stat(path)
+
+		return data, err == nil && time.Since(info.ModTime()) > brokenLockAge
+	}
+
+	return data, time.Now().After(lock.ExpiresAt)
+}
+
+// takeOver removes the stale lock at path. The lock is renamed away first and
+// only removed when it still holds the stale content; a lock another process
+// took over meanwhile is linked back instead, and takeOver reports false.
+func (s *FileLockStore) takeOver(path string, stale []byte) (bool, error) {
+	moved := path + "." + uuid.NewString() + ".stale"
+
+	if err := os.Rename(path, moved); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	current, err := os.ReadFile(moved)
+
+	if err != nil {
+		return false, errors.Join(err, os.Remove(moved))
+	}
+
+	if bytes.Equal(current, stale) {
+		return true, os.Remove(moved)
+	}
+
+	if err := os.Link(moved, path); err != nil && !errors.Is(err, os.ErrExist) {
+		return false, errors.Join(err, os.Remove(moved))
+	}
+
+	return false, os.Remove(moved)
+}
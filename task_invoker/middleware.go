@@ -0,0 +1,175 @@
+package task_invoker
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+)
+
+// Middleware wraps every attempt of a task. It wraps TaskFN rather than
+// InvokerFN so it can see the task name, arguments and context; adapt
+// InvokerFN middleware with WrapInvokerFN.
+type Middleware func(next TaskFN) TaskFN
+
+// WrapInvokerFN adapts middleware written for InvokerFN.
+func WrapInvokerFN(middleware func(next InvokerFN) InvokerFN) Middleware {
+	return func(next TaskFN) TaskFN {
+		return func(tc *TaskContext) error {
+			return middleware(func() error { return next(tc) })()
+		}
+	}
+}
+
+// Use appends middleware shared by every task. The first middleware is the
+// outermost one.
+func (i *Invoker) Use(middleware ...Middleware) {
+	i.middleware = append(i.middleware, middleware...)
+}
+
+// chain wraps the task Run with the invoker middleware, then the task
+// middleware.
+func (i *Invoker) chain(task *Task) TaskFN {
+	middleware := task.Middleware
+
+	if !task.ReplaceMiddleware {
+		middleware = append(slices.Clone(i.middleware), task.Middleware...)
+	}
+
+	run := task.Run
+
+	for _, m := range slices.Backward(middleware) {
+		run = m(run)
+	}
+
+	return run
+}
+
+// Recover turns a panic of the task into a *slice_utils.PanicError.
+func Recover() Middleware {
+	return func(next TaskFN) TaskFN {
+		return func(tc *TaskContext) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &slice_utils.PanicError{Value: r, Stack: debug.Stack()}
+				}
+			}()
+
+			return next(tc)
+		}
+	}
+}
+
+// Timing logs the duration and error of every attempt to logger.
+func Timing(logger *slog.Logger) Middleware {
+	return func(next TaskFN) TaskFN {
+		return func(tc *TaskContext) error {
+			start := time.Now()
+			err := next(tc)
+			attrs := []any{slog.String("task", tc.Name), slog.Duration("duration", time.Since(start))}
+
+			if err != nil {
+				logger.Error("task finished", append(attrs, slog.Any("error", err))...)
+			} else {
+				logger.Info("task finished", attrs...)
+			}
+
+			return err
+		}
+	}
+}
+
+// Metrics counts task attempts by outcome and their durations.
+type Metrics struct {
+	mu    sync.Mutex
+	tasks map[string]*taskMetrics
+}
+
+type taskMetrics struct {
+	outcomes     map[RunOutcome]int
+	count        int
+	durationSum  float64
+	lastDuration float64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{tasks: map[string]*taskMetrics{}}
+}
+
+func (m *Metrics) Middleware() Middleware {
+	return func(next TaskFN) TaskFN {
+		return func(tc *TaskContext) error {
+			start := time.Now()
+			err := next(tc)
+			m.observe(tc.Name, outcomeOf(err), time.Since(start))
+
+			return err
+		}
+	}
+}
+
+func (m *Metrics) observe(task string, outcome RunOutcome, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics, found := m.tasks[task]
+
+	if !found {
+		metrics = &taskMetrics{outcomes: map[RunOutcome]int{}}
+		m.tasks[task] = metrics
+	}
+
+	metrics.outcomes[outcome]++
+	metrics.count++
+	metrics.durationSum += duration.Seconds()
+	metrics.lastDuration = duration.Seconds()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteTo dumps the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := &strings.Builder{}
+	tasks := slices.Sorted(maps.Keys(m.tasks))
+
+	fmt.Fprintln(out, "# HELP task_attempts_total Task attempts by outcome.")
+	fmt.Fprintln(out, "# TYPE task_attempts_total counter")
+
+	for _, task := range tasks {
+		outcomes := m.tasks[task].outcomes
+
+		for _, outcome := range slices.Sorted(maps.Keys(outcomes)) {
+			fmt.Fprintf(out, "task_attempts_total{task=\"%s\",outcome=\"%s\"} %d\n", labelEscaper.Replace(task), outcome, outcomes[outcome])
+		}
+	}
+
+	fmt.Fprintln(out, "# HELP task_attempt_duration_seconds Task attempt durations.")
+	fmt.Fprintln(out, "# TYPE task_attempt_duration_seconds summary")
+
+	for _, task := range tasks {
+		label := labelEscaper.Replace(task)
+		fmt.Fprintf(out, "task_attempt_duration_seconds_sum{task=\"%s\"} %g\n", label, m.tasks[task].durationSum)
+		fmt.Fprintf(out, "task_attempt_duration_seconds_count{task=\"%s\"} %d\n", label, m.tasks[task].count)
+	}
+
+	fmt.Fprintln(out, "# HELP task_last_attempt_duration_seconds Duration of the last task attempt.")
+	fmt.Fprintln(out, "# TYPE task_last_attempt_duration_seconds gauge")
+
+	for _, task := range tasks {
+		fmt.Fprintf(out, "task_last_attempt_duration_seconds{task=\"%s\"} %g\n", labelEscaper.Replace(task), m.tasks[task].lastDuration)
+	}
+
+	n, err := io.WriteString(w, out.String())
+
+	return int64(n), err
+}
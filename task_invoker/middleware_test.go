@@ -0,0 +1,177 @@
+package task_invoker_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/Nuanu-com/go-utils/slice_utils"
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Middleware", func() {
+	newInvoker := func(name string, tasks ...task_invoker.Task) *task_invoker.Invoker {
+		invoker := task_invoker.NewInvoker(name, &cobra.Command{}, nil)
+		invoker.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+		for _, task := range tasks {
+			invoker.AddTask(task)
+		}
+
+		return invoker
+	}
+
+	trace := func(calls *[]string, label string) task_invoker.Middleware {
+		return func(next task_invoker.TaskFN) task_invoker.TaskFN {
+			return func(tc *task_invoker.TaskContext) error {
+				*calls = append(*calls, label+" before "+tc.Name)
+				err := next(tc)
+				*calls = append(*calls, label+" after "+tc.Name)
+
+				return err
+			}
+		}
+	}
+
+	It("runs the invoker middleware outermost first, then the task middleware", func(ctx SpecContext) {
+		calls := []string{}
+		invoker := newInvoker("greet", task_invoker.Task{
+			Name:       "greet",
+			Middleware: []task_invoker.Middleware{trace(&calls, "task")},
+			Run: func(*task_invoker.TaskContext) error {
+				calls = append(calls, "run")
+				return nil
+			},
+		})
+		invoker.Use(trace(&calls, "outer"), trace(&calls, "inner"))
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(calls).To(Equal([]string{
+			"outer before greet",
+			"inner before greet",
+			"task before greet",
+			"run",
+			"task after greet",
+			"inner after greet",
+			"outer after greet",
+		}))
+	})
+
+	It("lets a task replace the invoker middleware", func(ctx SpecContext) {
+		calls := []string{}
+		invoker := newInvoker("quiet", task_invoker.Task{
+			Name:              "quiet",
+			Middleware:        []task_invoker.Middleware{trace(&calls, "task")},
+			ReplaceMiddleware: true,
+			Run:               func(*task_invoker.TaskContext) error { return nil },
+		})
+		invoker.Use(trace(&calls, "outer"))
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(calls).To(Equal([]string{"task before quiet", "task after quiet"}))
+	})
+
+	It("wraps tasks registered with Add", func(ctx SpecContext) {
+		calls := []string{}
+		invoker := newInvoker("legacy")
+		invoker.Add("legacy", func() error { return nil })
+		invoker.Use(trace(&calls, "outer"))
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(calls).To(Equal([]string{"outer before legacy", "outer after legacy"}))
+	})
+
+	It("recovers panics into errors", func(ctx SpecContext) {
+		invoker := newInvoker("explode")
+		invoker.Add("explode", func() error { panic("boom") })
+		invoker.Use(task_invoker.Recover())
+
+		err := invoker.RunContext(ctx)
+
+		var panicErr *slice_utils.PanicError
+		Expect(errors.As(err, &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal("boom"))
+		Expect(panicErr.Stack).NotTo(BeEmpty())
+	})
+
+	It("logs the timing of every attempt", func(ctx SpecContext) {
+		logs := &bytes.Buffer{}
+		invoker := newInvoker("fail")
+		invoker.Add("fail", func() error { return errors.New("nope") })
+		invoker.Use(task_invoker.Timing(slog.New(slog.NewJSONHandler(logs, nil))))
+
+		Expect(invoker.RunContext(ctx)).To(MatchError("nope"))
+
+		var entry map[string]any
+		Expect(json.Unmarshal(logs.Bytes(), &entry)).To(Succeed())
+		Expect(entry).To(HaveKeyWithValue("msg", "task finished"))
+		Expect(entry).To(HaveKeyWithValue("task", "fail"))
+		Expect(entry).To(HaveKeyWithValue("error", "nope"))
+		Expect(entry).To(HaveKey("duration"))
+	})
+
+	It("adapts InvokerFN middleware", func(ctx SpecContext) {
+		calls := 0
+		alert := func(next task_invoker.InvokerFN) task_invoker.InvokerFN {
+			return func() error {
+				calls++
+
+				if err := next(); err != nil {
+					return fmt.Errorf("alerted: %w", err)
+				}
+
+				return nil
+			}
+		}
+
+		invoker := newInvoker("fail")
+		invoker.AddContext("fail", func(ctx context.Context) error {
+			tc, ok := task_invoker.TaskContextFrom(ctx)
+			Expect(ok).To(BeTrue())
+
+			return errors.New(tc.Name)
+		})
+		invoker.Use(task_invoker.WrapInvokerFN(alert))
+
+		Expect(invoker.RunContext(ctx)).To(MatchError("alerted: fail"))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("dumps Prometheus text metrics", func(ctx SpecContext) {
+		metrics := task_invoker.NewMetrics()
+		invoker := newInvoker("ok")
+		invoker.Add("ok", func() error { return nil })
+		invoker.Use(metrics.Middleware())
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+
+		failing := newInvoker("fail")
+		failing.AddTask(task_invoker.Task{Name: "fail", Run: func(*task_invoker.TaskContext) error { return errors.New("nope") }})
+		failing.Use(metrics.Middleware())
+		Expect(failing.RunContext(ctx)).NotTo(Succeed())
+
+		out := &bytes.Buffer{}
+		n, err := metrics.WriteTo(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(BeEquivalentTo(out.Len()))
+
+		lines := strings.Split(out.String(), "\n")
+		Expect(lines).To(ContainElements(
+			"# TYPE task_attempts_total counter",
+			`task_attempts_total{task="fail",outcome="failed"} 1`,
+			`task_attempts_total{task="ok",outcome="succeeded"} 2`,
+			`task_attempt_duration_seconds_count{task="ok"} 2`,
+		))
+		Expect(out.String()).To(ContainSubstring(`task_last_attempt_duration_seconds{task="ok"}`))
+	})
+})
@@ -0,0 +1,294 @@
+package task_invoker
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultJobMaxAttempts = 5
+
+var (
+	ErrNoJobs = errors.New("no job is due")
+	// ErrJobLost is returned when a job changed since it was dequeued, e.g.
+	// its visibility timeout expired and another worker claimed it.
+	ErrJobLost = errors.New("job is no longer held by this worker")
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobDead      JobStatus = "dead"
+)
+
+type Job struct {
+	ID      string          `json:"id"`
+	Task    string          `json:"task"`
+	Payload json.RawMessage `json:"payload"`
+	// Args are parsed by the task like command line arguments.
+	Args []string `json:"args"`
+	// Higher priorities are dequeued first.
+	Priority    int       `json:"priority"`
+	RunAt       time.Time `json:"run_at"`
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	Status      JobStatus `json:"status"`
+	LastError   string    `json:"last_error,omitempty"`
+	// LockedUntil hides a running job from Dequeue until its visibility
+	// timeout expires.
+	LockedUntil time.Time `json:"locked_until"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type EnqueueOptions struct {
+	Args []string
+	// Delay postpones the job. It is ignored when RunAt is set.
+	Delay    time.Duration
+	RunAt    time.Time
+	Priority int
+	// MaxAttempts before the job is moved to the dead letters. Zero uses
+	// DefaultJobMaxAttempts.
+	MaxAttempts int
+}
+
+// Queue stores jobs for the WorkerPool. Complete, Retry and Kill only apply
+// to the job as it was dequeued and fail with ErrJobLost otherwise.
+type Queue interface {
+	Enqueue(ctx context.Context, task string, payload any, opts EnqueueOptions) (*Job, error)
+	// Dequeue claims the next due job, highest priority first, and hides it
+	// for visibility. It fails with ErrNoJobs when no job is due.
+	Dequeue(ctx context.Context, visibility time.Duration) (*Job, error)
+	Complete(ctx context.Context, job *Job) error
+	// Retry releases the job to run again at runAt.
+	Retry(ctx context.Context, job *Job, cause error, runAt time.Time) error
+	// Kill moves the job to the dead letters.
+	Kill(ctx context.Context, job *Job, cause error) error
+	// DeadLetters returns the dead jobs, most recent first.
+	DeadLetters(ctx context.Context, limit int) ([]Job, error)
+	// Requeue resets a dead job to run again immediately.
+	Requeue(ctx context.Context, id string) error
+}
+
+func newJob(task string, payload any, opts EnqueueOptions) (*Job, error) {
+	data, err := json.Marshal(payload)
+
+	if err != nil {
+		return nil, fmt.Errorf("job payload: %w", err)
+	}
+
+	now := time.Now()
+	runAt := opts.RunAt
+
+	if runAt.IsZero() {
+		runAt = now.Add(opts.Delay)
+	}
+
+	maxAttempts := opts.MaxAttempts
+
+	if maxAttempts < 1 {
+		maxAttempts = DefaultJobMaxAttempts
+	}
+
+	return &Job{
+		ID:          uuid.NewString(),
+		Task:        task,
+		Payload:     data,
+		Args:        slices.Clone(opts.Args),
+		Priority:    opts.Priority,
+		RunAt:       runAt,
+		MaxAttempts: maxAttempts,
+		Status:      JobPending,
+		CreatedAt:   now,
+	}, nil
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+// MemoryQueue keeps the jobs in process, e.g. for tests and single process
+// services.
+type MemoryQueue struct {
+	mu   sync.Mutex
+	jobs []*Job
+}
+
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{}
+}
+
+func (q *MemoryQueue) Enqueue(ctx context.Context, task string, payload any, opts EnqueueOptions) (*Job, error) {
+	job, err := newJob(task, payload, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored := *job
+	q.jobs = append(q.jobs, &stored)
+
+	return job, nil
+}
+
+func (q *MemoryQueue) Dequeue(ctx context.Context, visibility time.Duration) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	var next *Job
+
+	for _, job := range q.jobs {
+		due := (job.Status == JobPending && !job.RunAt.After(now)) ||
+			(job.Status == JobRunning && !job.LockedUntil.After(now))
+
+		if due && (next == nil || compareJobs(job, next) < 0) {
+			next = job
+		}
+	}
+
+	if next == nil {
+		return nil, ErrNoJobs
+	}
+
+	next.Status = JobRunning
+	next.Attempts++
+	next.LockedUntil = now.Add(visibility)
+
+	job := *next
+
+	return &job, nil
+}
+
+// compareJobs orders the jobs as they are dequeued.
+func compareJobs(a *Job, b *Job) int {
+	return cmp.Or(
+		cmp.Compare(b.Priority, a.Priority),
+		a.RunAt.Compare(b.RunAt),
+		a.CreatedAt.Compare(b.CreatedAt),
+	)
+}
+
+// held returns the stored job when it is still running the attempt of job.
+func (q *MemoryQueue) held(job *Job) (*Job, error) {
+	for _, stored := range q.jobs {
+		if stored.ID == job.ID && stored.Status == JobRunning && stored.Attempts == job.Attempts {
+			return stored, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrJobLost, job.ID)
+}
+
+func (q *MemoryQueue) Complete(ctx context.Context, job *Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, err := q.held(job)
+
+	if err != nil {
+		return err
+	}
+
+	stored.Status = JobSucceeded
+	stored.LockedUntil = time.Time{}
+
+	return nil
+}
+
+func (q *MemoryQueue) Retry(ctx context.Context, job *Job, cause error, runAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, err := q.held(job)
+
+	if err != nil {
+		return err
+	}
+
+	stored.Status = JobPending
+	stored.RunAt = runAt
+	stored.LastError = errorText(cause)
+	stored.LockedUntil = time.Time{}
+
+	return nil
+}
+
+func (q *MemoryQueue) Kill(ctx context.Context, job *Job, cause error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, err := q.held(job)
+
+	if err != nil {
+		return err
+	}
+
+	stored.Status = JobDead
+	stored.LastError = errorText(cause)
+	stored.LockedUntil = time.Time{}
+
+	return nil
+}
+
+func (q *MemoryQueue) DeadLetters(ctx context.Context, limit int) ([]Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	results := []Job{}
+
+	for _, job := range slices.Backward(q.jobs) {
+		if job.Status == JobDead && (limit <= 0 || len(results) < limit) {
+			results = append(results, *job)
+		}
+	}
+
+	return results, nil
+}
+
+func (q *MemoryQueue) Requeue(ctx context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range q.jobs {
+		if job.ID == id && job.Status == JobDead {
+			job.Status = JobPending
+			job.Attempts = 0
+			job.RunAt = time.Now()
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("dead job %s not found", id)
+}
+
+// Job returns a copy of the stored job.
+func (q *MemoryQueue) Job(id string) (Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range q.jobs {
+		if job.ID == id {
+			return *job, true
+		}
+	}
+
+	return Job{}, false
+}
@@ -0,0 +1,262 @@
+package task_invoker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/internal/sql_utils"
+	"github.com/Nuanu-com/go-utils/pagination"
+)
+
+// SQLQueue stores jobs in a table shaped like:
+//
+//	CREATE TABLE jobs (
+//		id           TEXT PRIMARY KEY,
+//		task         TEXT NOT NULL,
+//		payload      TEXT NOT NULL,
+//		args         TEXT NOT NULL,
+//		priority     INTEGER NOT NULL,
+//		run_at       TIMESTAMP NOT NULL,
+//		attempts     INTEGER NOT NULL,
+//		max_attempts INTEGER NOT NULL,
+//		status       TEXT NOT NULL,
+//		last_error   TEXT NOT NULL,
+//		locked_until TIMESTAMP NOT NULL,
+//		created_at   TIMESTAMP NOT NULL
+//	);
+//	CREATE INDEX jobs_due ON jobs (status, priority DESC, run_at);
+//
+// Dequeue claims a job with a single UPDATE ... RETURNING statement selecting
+// the job in a subquery on the same table. PostgreSQL and SQLite 3.35+
+// support it; MySQL and MariaDB reject it and are not supported.
+type SQLQueue struct {
+	DB DB
+	// Table is interpolated into the queries; NewSQLQueue checks that it is
+	// a plain identifier.
+	Table       string
+	Placeholder pagination.Placeholder
+	// LockClause ends the subquery selecting the next job. NewSQLQueue sets
+	// "FOR UPDATE SKIP LOCKED" so concurrent workers skip each other's rows;
+	// clear it for SQLite, which serializes writes instead.
+	LockClause string
+}
+
+const sqlQueueColumns = "id, task, payload, args, priority, run_at, attempts, max_attempts, status, last_error, locked_until, created_at"
+
+// NewSQLQueue fails on a table that is not a plain identifier, since it is
+// interpolated into the queries.
+func NewSQLQueue(db DB, table string, placeholder pagination.Placeholder) (*SQLQueue, error) {
+	if !sql_utils.IsIdentifier(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+
+	return &SQLQueue{DB: db, Table: table, Placeholder: placeholder, LockClause: "FOR UPDATE SKIP LOCKED"}, nil
+}
+
+func (q *SQLQueue) placeholders(from int, count int) []string {
+	results := make([]string, count)
+
+	for idx := range results {
+		results[idx] = q.Placeholder(from + idx)
+	}
+
+	return results
+}
+
+func (q *SQLQueue) Enqueue(ctx context.Context, task string, payload any, opts EnqueueOptions) (*Job, error) {
+	job, err := newJob(task, payload, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := json.Marshal(job.Args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := []any{
+		job.ID, job.Task, string(job.Payload), string(args), job.Priority, job.RunAt, job.Attempts,
+		job.MaxAttempts, string(job.Status), job.LastError, job.LockedUntil, job.CreatedAt,
+	}
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		q.Table, sqlQueueColumns, strings.Join(q.placeholders(1, len(values)), ", "),
+	)
+
+	if _, err := q.DB.ExecContext(ctx, query, values...); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (q *SQLQueue) Dequeue(ctx context.Context, visibility time.Duration) (*Job, error) {
+	now := time.Now()
+	p := q.placeholders(1, 6)
+	query := fmt.Sprintf(`UPDATE %[1]s SET status = %[2]s, attempts = attempts + 1, locked_until = %[3]s
+WHERE id = (
+	SELECT id FROM %[1]s
+	WHERE (status = %[4]s AND run_at <= %[5]s) OR (status = %[6]s AND locked_until <= %[7]s)
+	ORDER BY priority DESC, run_at, created_at
+	LIMIT 1 %[8]s
+)
+RETURNING %[9]s`, q.Table, p[0], p[1], p[2], p[3], p[4], p[5], q.LockClause, sqlQueueColumns)
+
+	rows, err := q.DB.QueryContext(ctx, query, string(JobRunning), now.Add(visibility), string(JobPending), now, string(JobRunning), now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := scanJobs(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, ErrNoJobs
+	}
+
+	return &jobs[0], nil
+}
+
+func scanJobs(rows *sql.Rows) ([]Job, error) {
+	defer rows.Close()
+
+	results := []Job{}
+
+	for rows.Next() {
+		var (
+			job     Job
+			payload string
+			args    string
+			status  string
+		)
+
+		err := rows.Scan(
+			&job.ID, &job.Task, &payload, &args, &job.Priority, &job.RunAt, &job.Attempts,
+			&job.MaxAttempts, &status, &job.LastError, &job.LockedUntil, &job.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		job.Payload = json.RawMessage(payload)
+		job.Status = JobStatus(status)
+
+		if err := json.Unmarshal([]byte(args), &job.Args); err != nil {
+			return nil, err
+		}
+
+		results = append(results, job)
+	}
+
+	return results, rows.Err()
+}
+
+// update applies set to the job while it still runs the dequeued attempt.
+func (q *SQLQueue) update(ctx context.Context, job *Job, set string, values ...any) error {
+	p := q.placeholders(len(values)+1, 3)
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE id = %s AND status = %s AND attempts = %s",
+		q.Table, set, p[0], p[1], p[2],
+	)
+
+	result, err := q.DB.ExecContext(ctx, query, append(values, job.ID, string(JobRunning), job.Attempts)...)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", ErrJobLost, job.ID)
+	}
+
+	return nil
+}
+
+func (q *SQLQueue) Complete(ctx context.Context, job *Job) error {
+	p := q.placeholders(1, 2)
+
+	return q.update(ctx, job,
+		fmt.Sprintf("status = %s, locked_until = %s", p[0], p[1]),
+		string(JobSucceeded), time.Time{},
+	)
+}
+
+func (q *SQLQueue) Retry(ctx context.Context, job *Job, cause error, runAt time.Time) error {
+	p := q.placeholders(1, 4)
+
+	return q.update(ctx, job,
+		fmt.Sprintf("status = %s, run_at = %s, last_error = %s, locked_until = %s", p[0], p[1], p[2], p[3]),
+		string(JobPending), runAt, errorText(cause), time.Time{},
+	)
+}
+
+func (q *SQLQueue) Kill(ctx context.Context, job *Job, cause error) error {
+	p := q.placeholders(1, 3)
+
+	return q.update(ctx, job,
+		fmt.Sprintf("status = %s, last_error = %s, locked_until = %s", p[0], p[1], p[2]),
+		string(JobDead), errorText(cause), time.Time{},
+	)
+}
+
+func (q *SQLQueue) DeadLetters(ctx context.Context, limit int) ([]Job, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE status = %s ORDER BY created_at DESC",
+		sqlQueueColumns, q.Table, q.Placeholder(1),
+	)
+
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := q.DB.QueryContext(ctx, query, string(JobDead))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return scanJobs(rows)
+}
+
+func (q *SQLQueue) Requeue(ctx context.Context, id string) error {
+	p := q.placeholders(1, 4)
+	query := fmt.Sprintf(
+		"UPDATE %s SET status = %s, attempts = 0, run_at = %s WHERE id = %s AND status = %s",
+		q.Table, p[0], p[1], p[2], p[3],
+	)
+
+	result, err := q.DB.ExecContext(ctx, query, string(JobPending), time.Now(), id, string(JobDead))
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("dead job %s not found", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package task_invoker_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/pagination"
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	_ "github.com/mattn/go-sqlite3"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// openSQLite opens a fresh database file running schema. The busy timeout
+// lets concurrent writers wait for each other instead of failing.
+func openSQLite(schema string) *sql.DB {
+	path := filepath.Join(GinkgoT().TempDir(), "test.db")
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	Expect(err).NotTo(HaveOccurred())
+
+	DeferCleanup(db.Close)
+
+	_, err = db.Exec(schema)
+	Expect(err).NotTo(HaveOccurred())
+
+	return db
+}
+
+const jobsSchema = `CREATE TABLE jobs (
+	id           TEXT PRIMARY KEY,
+	task         TEXT NOT NULL,
+	payload      TEXT NOT NULL,
+	args         TEXT NOT NULL,
+	priority     INTEGER NOT NULL,
+	run_at       TIMESTAMP NOT NULL,
+	attempts     INTEGER NOT NULL,
+	max_attempts INTEGER NOT NULL,
+	status       TEXT NOT NULL,
+	last_error   TEXT NOT NULL,
+	locked_until TIMESTAMP NOT NULL,
+	created_at   TIMESTAMP NOT NULL
+);
+CREATE INDEX jobs_due ON jobs (status, priority DESC, run_at);`
+
+var _ = Describe("SQLQueue", func() {
+	var queue *task_invoker.SQLQueue
+
+	BeforeEach(func() {
+		var err error
+
+		queue, err = task_invoker.NewSQLQueue(openSQLite(jobsSchema), "jobs", pagination.QuestionPlaceholder)
+		Expect(err).NotTo(HaveOccurred())
+
+		queue.LockClause = ""
+	})
+
+	It("rejects table names that are not identifiers", func() {
+		_, err := task_invoker.NewSQLQueue(nil, "jobs; DROP TABLE jobs", pagination.QuestionPlaceholder)
+		Expect(err).To(MatchError(`invalid table name "jobs; DROP TABLE jobs"`))
+	})
+
+	It("dequeues due jobs by priority, then run time", func(ctx SpecContext) {
+		low, err := queue.Enqueue(ctx, "email", map[string]string{"to": "a"}, task_invoker.EnqueueOptions{Args: []string{"--dry"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		high, _ := queue.Enqueue(ctx, "email", map[string]string{"to": "b"}, task_invoker.EnqueueOptions{Priority: 10})
+		_, _ = queue.Enqueue(ctx, "email", nil, task_invoker.EnqueueOptions{Delay: time.Hour, Priority: 100})
+
+		first, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.ID).To(Equal(high.ID))
+		Expect(first.Status).To(Equal(task_invoker.JobRunning))
+		Expect(first.Attempts).To(Equal(1))
+		Expect(string(first.Payload)).To(Equal(`{"to":"b"}`))
+
+		second, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.ID).To(Equal(low.ID))
+		Expect(second.Args).To(Equal([]string{"--dry"}))
+
+		_, err = queue.Dequeue(ctx, time.Minute)
+		Expect(err).To(MatchError(task_invoker.ErrNoJobs))
+	})
+
+	It("hands a job out again once its visibility timeout expires", func(ctx SpecContext) {
+		job, _ := queue.Enqueue(ctx, "email", nil, task_invoker.EnqueueOptions{})
+
+		first, err := queue.Dequeue(ctx, 50*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = queue.Dequeue(ctx, time.Minute)
+		Expect(err).To(MatchError(task_invoker.ErrNoJobs))
+
+		time.Sleep(100 * time.Millisecond)
+
+		second, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.ID).To(Equal(job.ID))
+		Expect(second.Attempts).To(Equal(2))
+
+		Expect(queue.Complete(ctx, first)).To(MatchError(task_invoker.ErrJobLost))
+		Expect(queue.Complete(ctx, second)).To(Succeed())
+
+		_, err = queue.Dequeue(ctx, time.Minute)
+		Expect(err).To(MatchError(task_invoker.ErrNoJobs))
+	})
+
+	It("retries, kills and requeues jobs", func(ctx SpecContext) {
+		job, _ := queue.Enqueue(ctx, "email", nil, task_invoker.EnqueueOptions{})
+		running, _ := queue.Dequeue(ctx, time.Minute)
+
+		Expect(queue.Retry(ctx, running, errors.New("timeout"), time.Now().Add(time.Hour))).To(Succeed())
+		Expect(queue.Retry(ctx, running, errors.New("timeout"), time.Now())).To(MatchError(task_invoker.ErrJobLost))
+
+		_, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).To(MatchError(task_invoker.ErrNoJobs))
+
+		// Only dead jobs are requeued.
+		Expect(queue.Requeue(ctx, job.ID)).NotTo(Succeed())
+
+		job2, _ := queue.Enqueue(ctx, "email", nil, task_invoker.EnqueueOptions{})
+		running, _ = queue.Dequeue(ctx, time.Minute)
+		Expect(running.ID).To(Equal(job2.ID))
+
+		Expect(queue.Kill(ctx, running, errors.New("bounced"))).To(Succeed())
+
+		dead, err := queue.DeadLetters(ctx, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dead).To(HaveLen(1))
+		Expect(dead[0].ID).To(Equal(job2.ID))
+		Expect(dead[0].Status).To(Equal(task_invoker.JobDead))
+		Expect(dead[0].LastError).To(Equal("bounced"))
+
+		Expect(queue.Requeue(ctx, job2.ID)).To(Succeed())
+		Expect(queue.Requeue(ctx, job2.ID)).NotTo(Succeed())
+
+		again, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again.ID).To(Equal(job2.ID))
+		Expect(again.Attempts).To(Equal(1))
+		Expect(again.LastError).To(Equal("bounced"))
+	})
+
+	It("never hands the same job to concurrent workers", func(ctx SpecContext) {
+		for range 10 {
+			_, err := queue.Enqueue(ctx, "email", nil, task_invoker.EnqueueOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		var (
+			mu   sync.Mutex
+			seen = map[string]int{}
+			wg   sync.WaitGroup
+		)
+
+		for range 2 {
+			wg.Add(1)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				for {
+					job, err := queue.Dequeue(ctx, time.Minute)
+
+					if errors.Is(err, task_invoker.ErrNoJobs) {
+						return
+					}
+
+					Expect(err).NotTo(HaveOccurred())
+
+					mu.Lock()
+					seen[job.ID]++
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		Expect(seen).To(HaveLen(10))
+
+		for _, count := range seen {
+			Expect(count).To(Equal(1))
+		}
+	})
+})
@@ -0,0 +1,250 @@
+package task_invoker_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MemoryQueue", func() {
+	var queue *task_invoker.MemoryQueue
+
+	BeforeEach(func() {
+		queue = task_invoker.NewMemoryQueue()
+	})
+
+	It("dequeues due jobs by priority, then run time", func(ctx SpecContext) {
+		low, _ := queue.Enqueue(ctx, "email", map[string]string{"to": "a"}, task_invoker.EnqueueOptions{})
+		high, _ := queue.Enqueue(ctx, "email", map[string]string{"to": "b"}, task_invoker.EnqueueOptions{Priority: 10})
+		_, _ = queue.Enqueue(ctx, "email", nil, task_invoker.EnqueueOptions{Delay: time.Hour, Priority: 100})
+
+		first, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.ID).To(Equal(high.ID))
+		Expect(first.Status).To(Equal(task_invoker.JobRunning))
+		Expect(first.Attempts).To(Equal(1))
+		Expect(string(first.Payload)).To(Equal(`{"to":"b"}`))
+
+		second, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.ID).To(Equal(low.ID))
+
+		_, err = queue.Dequeue(ctx, time.Minute)
+		Expect(err).To(MatchError(task_invoker.ErrNoJobs))
+	})
+
+	It("hands a job out again once its visibility timeout expires", func(ctx SpecContext) {
+		job, _ := queue.Enqueue(ctx, "email", nil, task_invoker.EnqueueOptions{})
+
+		first, _ := queue.Dequeue(ctx, 10*time.Millisecond)
+		_, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).To(MatchError(task_invoker.ErrNoJobs))
+
+		time.Sleep(20 * time.Millisecond)
+
+		second, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.ID).To(Equal(job.ID))
+		Expect(second.Attempts).To(Equal(2))
+
+		Expect(queue.Complete(ctx, first)).To(MatchError(task_invoker.ErrJobLost))
+		Expect(queue.Complete(ctx, second)).To(Succeed())
+	})
+
+	It("moves killed jobs to the dead letters and requeues them", func(ctx SpecContext) {
+		job, _ := queue.Enqueue(ctx, "email", nil, task_invoker.EnqueueOptions{})
+		running, _ := queue.Dequeue(ctx, time.Minute)
+
+		Expect(queue.Kill(ctx, running, errors.New("bounced"))).To(Succeed())
+
+		dead, err := queue.DeadLetters(ctx, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dead).To(HaveLen(1))
+		Expect(dead[0].ID).To(Equal(job.ID))
+		Expect(dead[0].LastError).To(Equal("bounced"))
+
+		Expect(queue.Requeue(ctx, job.ID)).To(Succeed())
+		Expect(queue.Requeue(ctx, job.ID)).NotTo(Succeed())
+
+		again, err := queue.Dequeue(ctx, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again.Attempts).To(Equal(1))
+	})
+})
+
+var _ = Describe("WorkerPool", func() {
+	var (
+		queue   *task_invoker.MemoryQueue
+		invoker *task_invoker.Invoker
+	)
+
+	BeforeEach(func() {
+		queue = task_invoker.NewMemoryQueue()
+		invoker = task_invoker.NewInvoker("", &cobra.Command{}, nil)
+		invoker.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	})
+
+	start := func(ctx context.Context) (stop func()) {
+		pool := task_invoker.NewWorkerPool(invoker, queue, task_invoker.WorkerOptions{
+			Concurrency:  2,
+			PollInterval: time.Millisecond,
+			Backoff:      &task_invoker.RetryPolicy{InitialBackoff: time.Millisecond},
+		})
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+
+		go func() { done <- pool.Run(ctx) }()
+
+		return func() {
+			cancel()
+			Expect(<-done).To(Succeed())
+		}
+	}
+
+	status := func(id string) task_invoker.JobStatus {
+		job, _ := queue.Job(id)
+		return job.Status
+	}
+
+	It("runs the jobs with their payload and args", func(ctx SpecContext) {
+		var (
+			mu   sync.Mutex
+			seen []string
+		)
+
+		invoker.AddTask(task_invoker.Task{
+			Name: "email",
+			Args: []task_invoker.TaskArg{{Name: "template"}},
+			Run: func(tc *task_invoker.TaskContext) error {
+				var payload struct{ To string }
+
+				if err := tc.Payload(&payload); err != nil {
+					return err
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				seen = append(seen, tc.Arg("template")+":"+payload.To)
+
+				return nil
+			},
+		})
+
+		job, err := queue.Enqueue(ctx, "email", map[string]string{"to": "ops@example.com"}, task_invoker.EnqueueOptions{
+			Args: []string{"welcome"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		stop := start(ctx)
+		Eventually(func() task_invoker.JobStatus { return status(job.ID) }).Should(Equal(task_invoker.JobSucceeded))
+		stop()
+
+		Expect(seen).To(Equal([]string{"welcome:ops@example.com"}))
+	})
+
+	It("does not parse the worker arguments for jobs without args", func(ctx SpecContext) {
+		setProcessArgs("server", "--port", "8080")
+
+		invoker.AddTask(task_invoker.Task{
+			Name:  "digest",
+			Flags: func(flags *pflag.FlagSet) { flags.Bool("weekly", false, "send the weekly digest") },
+			Run:   func(*task_invoker.TaskContext) error { return nil },
+		})
+
+		job, _ := queue.Enqueue(ctx, "digest", nil, task_invoker.EnqueueOptions{})
+
+		stop := start(ctx)
+		Eventually(func() task_invoker.JobStatus { return status(job.ID) }).Should(Equal(task_invoker.JobSucceeded))
+		stop()
+	})
+
+	It("retries failed jobs, then moves them to the dead letters", func(ctx SpecContext) {
+		var (
+			mu    sync.Mutex
+			calls int
+		)
+
+		invoker.AddTask(task_invoker.Task{
+			Name: "webhook",
+			Run: func(*task_invoker.TaskContext) error {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+
+				return errors.New("503")
+			},
+		})
+
+		job, _ := queue.Enqueue(ctx, "webhook", nil, task_invoker.EnqueueOptions{MaxAttempts: 3})
+		unknown, _ := queue.Enqueue(ctx, "missing", nil, task_invoker.EnqueueOptions{})
+
+		stop := start(ctx)
+		Eventually(func() task_invoker.JobStatus { return status(job.ID) }).Should(Equal(task_invoker.JobDead))
+		Eventually(func() task_invoker.JobStatus { return status(unknown.ID) }).Should(Equal(task_invoker.JobDead))
+		stop()
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(calls).To(Equal(3))
+
+		dead, _ := queue.Job(job.ID)
+		Expect(dead.LastError).To(Equal("503"))
+
+		missing, _ := queue.Job(unknown.ID)
+		Expect(missing.Attempts).To(Equal(1))
+		Expect(missing.LastError).To(ContainSubstring("not registered"))
+	})
+
+	It("waits for the delay", func(ctx SpecContext) {
+		invoker.Add("report", func() error { return nil })
+
+		job, _ := queue.Enqueue(ctx, "report", nil, task_invoker.EnqueueOptions{Delay: 50 * time.Millisecond})
+
+		stop := start(ctx)
+		Consistently(func() task_invoker.JobStatus { return status(job.ID) }, 30*time.Millisecond).Should(Equal(task_invoker.JobPending))
+		Eventually(func() task_invoker.JobStatus { return status(job.ID) }).Should(Equal(task_invoker.JobSucceeded))
+		stop()
+	})
+
+	It("releases jobs interrupted by the shutdown", func(ctx SpecContext) {
+		started := make(chan struct{})
+
+		invoker.AddContext("long", func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		job, _ := queue.Enqueue(ctx, "long", nil, task_invoker.EnqueueOptions{})
+
+		stop := start(ctx)
+		Eventually(started).Should(BeClosed())
+		stop()
+
+		Expect(status(job.ID)).To(Equal(task_invoker.JobPending))
+	})
+
+	It("fails Payload outside a job", func(ctx SpecContext) {
+		cli := task_invoker.NewInvoker("cli", &cobra.Command{}, nil)
+		cli.Logger = invoker.Logger
+		cli.AddTask(task_invoker.Task{
+			Name: "cli",
+			Run: func(tc *task_invoker.TaskContext) error {
+				var payload map[string]any
+				return tc.Payload(&payload)
+			},
+		})
+
+		Expect(cli.RunContext(ctx)).To(MatchError(task_invoker.ErrNoPayload))
+	})
+})
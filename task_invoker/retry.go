@@ -0,0 +1,150 @@
+package task_invoker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryPolicy struct {
+	// MaxAttempts includes the first run. Values below 1 mean a single run.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff. Zero leaves it uncapped.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every attempt. Values below 1 use 2.
+	Multiplier float64
+	// Jitter randomizes every backoff by up to ±Jitter of its value (0 to 1).
+	Jitter float64
+	// Retryable decides whether an error is worth another attempt. Nil retries
+	// every error except context cancellation.
+	Retryable func(err error) bool
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return !errors.Is(err, context.Canceled)
+}
+
+// Backoff returns the delay before the given attempt (2 for the first retry).
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-2))
+
+	if p.MaxBackoff > 0 {
+		backoff = min(backoff, float64(p.MaxBackoff))
+	}
+
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (rand.Float64()*2 - 1)
+	}
+
+	return time.Duration(backoff)
+}
+
+// invoke runs a parsed task under its lock and retry policy, logging every
+// attempt and recording the run.
+func (i *Invoker) invoke(tc *TaskContext) error {
+	startedAt := time.Now()
+	attempts, err := i.runAttempts(tc)
+
+	i.record(tc, startedAt, attempts, err)
+
+	return err
+}
+
+func (i *Invoker) runAttempts(tc *TaskContext) (int, error) {
+	task := tc.task
+
+	if i.Locks != nil {
+		ttl := task.LockTTL
+
+		if ttl == 0 {
+			ttl = DefaultLockTTL
+		}
+
+		release, err := i.Locks.Acquire(tc, task.Name, ttl)
+
+		if err != nil {
+			return 0, err
+		}
+
+		defer release()
+	}
+
+	attempts := task.Retry.maxAttempts()
+	logger := i.logger().With(slog.String("task", task.Name))
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := i.attempt(tc, task)
+		attrs := []any{
+			slog.Int("attempt", attempt),
+			slog.Int("max_attempts", attempts),
+			slog.Duration("duration", time.Since(start)),
+		}
+
+		if err == nil {
+			logger.Info("task attempt succeeded", attrs...)
+			return attempt, nil
+		}
+
+		attrs = append(attrs, slog.Any("error", err))
+
+		if attempt >= attempts || tc.Err() != nil || !task.Retry.retryable(err) {
+			logger.Error("task attempt failed", attrs...)
+			return attempt, err
+		}
+
+		backoff := task.Retry.Backoff(attempt + 1)
+		logger.Warn("task attempt failed, retrying", append(attrs, slog.Duration("backoff", backoff))...)
+
+		select {
+		case <-tc.Done():
+			return attempt, err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (i *Invoker) attempt(tc *TaskContext, task *Task) error {
+	ctx := tc.Context
+
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Timeout)
+		defer cancel()
+	}
+
+	attemptTc := *tc
+	attemptTc.Context = ctx
+
+	return i.chain(task)(&attemptTc)
+}
+
+func (i *Invoker) logger() *slog.Logger {
+	if i.Logger != nil {
+		return i.Logger
+	}
+
+	return slog.New(slog.DiscardHandler)
+}
@@ -0,0 +1,236 @@
+package task_invoker_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retries", func() {
+	var logs *bytes.Buffer
+
+	newInvoker := func(task task_invoker.Task) *task_invoker.Invoker {
+		invoker := task_invoker.NewInvoker(task.Name, &cobra.Command{}, nil)
+		invoker.Logger = slog.New(slog.NewJSONHandler(logs, nil))
+		invoker.AddTask(task)
+
+		return invoker
+	}
+
+	BeforeEach(func() {
+		logs = &bytes.Buffer{}
+	})
+
+	It("retries until the task succeeds and logs every attempt", func(ctx SpecContext) {
+		calls := 0
+		invoker := newInvoker(task_invoker.Task{
+			Name:  "flaky",
+			Retry: &task_invoker.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			Run: func(tc *task_invoker.TaskContext) error {
+				calls++
+				if calls < 3 {
+					return errors.New("partner timeout")
+				}
+				return nil
+			},
+		})
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+		Expect(calls).To(Equal(3))
+
+		lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+		Expect(lines).To(HaveLen(3))
+
+		var last map[string]any
+		Expect(json.Unmarshal([]byte(lines[2]), &last)).To(Succeed())
+		Expect(last).To(HaveKeyWithValue("task", "flaky"))
+		Expect(last).To(HaveKeyWithValue("attempt", float64(3)))
+		Expect(last).To(HaveKeyWithValue("msg", "task attempt succeeded"))
+	})
+
+	It("stops after the max attempts", func(ctx SpecContext) {
+		calls := 0
+		invoker := newInvoker(task_invoker.Task{
+			Name:  "broken",
+			Retry: &task_invoker.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			Run: func(tc *task_invoker.TaskContext) error {
+				calls++
+				return errors.New("down")
+			},
+		})
+
+		Expect(invoker.RunContext(ctx)).To(MatchError("down"))
+		Expect(calls).To(Equal(2))
+	})
+
+	It("does not retry non retryable errors", func(ctx SpecContext) {
+		permanent := errors.New("invalid credentials")
+		calls := 0
+		invoker := newInvoker(task_invoker.Task{
+			Name: "auth",
+			Retry: &task_invoker.RetryPolicy{
+				MaxAttempts:    5,
+				InitialBackoff: time.Millisecond,
+				Retryable:      func(err error) bool { return !errors.Is(err, permanent) },
+			},
+			Run: func(tc *task_invoker.TaskContext) error {
+				calls++
+				return permanent
+			},
+		})
+
+		Expect(invoker.RunContext(ctx)).To(MatchError(permanent))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("grows the backoff exponentially within the bounds", func() {
+		policy := &task_invoker.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+		Expect(policy.Backoff(2)).To(Equal(100 * time.Millisecond))
+		Expect(policy.Backoff(3)).To(Equal(200 * time.Millisecond))
+		Expect(policy.Backoff(10)).To(Equal(time.Second))
+
+		policy.Jitter = 0.5
+		Expect(policy.Backoff(3)).To(BeNumerically("~", 200*time.Millisecond, 100*time.Millisecond))
+	})
+})
+
+var _ = Describe("Lock stores", func() {
+	stores := map[string]func() task_invoker.LockStore{
+		"memory": func() task_invoker.LockStore { return task_invoker.NewMemoryLockStore() },
+		"file": func() task_invoker.LockStore {
+			store, err := task_invoker.NewFileLockStore(GinkgoT().TempDir())
+			Expect(err).NotTo(HaveOccurred())
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		Context(name, func() {
+			It("excludes concurrent holders until released", func(ctx SpecContext) {
+				store := newStore()
+
+				release, err := store.Acquire(ctx, "settle", time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = store.Acquire(ctx, "settle", time.Minute)
+				Expect(err).To(MatchError(task_invoker.ErrLocked))
+
+				Expect(release()).To(Succeed())
+
+				release, err = store.Acquire(ctx, "settle", time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(release()).To(Succeed())
+			})
+
+			It("takes over expired locks", func(ctx SpecContext) {
+				store := newStore()
+
+				_, err := store.Acquire(ctx, "settle", time.Millisecond)
+				Expect(err).NotTo(HaveOccurred())
+
+				time.Sleep(5 * time.Millisecond)
+
+				_, err = store.Acquire(ctx, "settle", time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	}
+
+	Context("file", func() {
+		var (
+			dir   string
+			store *task_invoker.FileLockStore
+		)
+
+		BeforeEach(func() {
+			dir = filepath.Join(GinkgoT().TempDir(), "locks")
+
+			var err error
+			store, err = task_invoker.NewFileLockStore(dir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("lets a single process take over a stale lock", func(ctx SpecContext) {
+			_, err := store.Acquire(ctx, "settle", time.Millisecond)
+			Expect(err).NotTo(HaveOccurred())
+
+			time.Sleep(5 * time.Millisecond)
+
+			var (
+				wg       sync.WaitGroup
+				acquired atomic.Int32
+			)
+
+			for range 8 {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					if _, err := store.Acquire(ctx, "settle", time.Minute); err == nil {
+						acquired.Add(1)
+					}
+				}()
+			}
+
+			wg.Wait()
+
+			Expect(acquired.Load()).To(BeEquivalentTo(1))
+		})
+
+		It("takes over old lock files left empty by a crash", func(ctx SpecContext) {
+			path := filepath.Join(dir, "settle.lock")
+			Expect(os.WriteFile(path, nil, 0o644)).To(Succeed())
+
+			_, err := store.Acquire(ctx, "settle", time.Minute)
+			Expect(err).To(MatchError(task_invoker.ErrLocked))
+
+			old := time.Now().Add(-time.Hour)
+			Expect(os.Chtimes(path, old, old)).To(Succeed())
+
+			release, err := store.Acquire(ctx, "settle", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(release()).To(Succeed())
+		})
+
+		It("keeps the lock files inside the directory", func(ctx SpecContext) {
+			release, err := store.Acquire(ctx, "../settle", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(dir, "..", "settle.lock")).NotTo(BeAnExistingFile())
+
+			entries, err := os.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+
+			Expect(release()).To(Succeed())
+		})
+	})
+
+	It("prevents the invoker from running a locked task", func(ctx SpecContext) {
+		store := task_invoker.NewMemoryLockStore()
+		release, err := store.Acquire(ctx, "settle", time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		defer release()
+
+		invoker := task_invoker.NewInvoker("settle", &cobra.Command{}, nil)
+		invoker.Locks = store
+		invoker.Add("settle", func() error { return nil })
+
+		Expect(invoker.RunContext(ctx)).To(MatchError(task_invoker.ErrLocked))
+	})
+})
@@ -0,0 +1,206 @@
+package task_invoker
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type CatchUpPolicy int
+
+const (
+	// CatchUpSkip drops the runs missed while the previous run was still
+	// going or the scheduler was down.
+	CatchUpSkip CatchUpPolicy = iota
+	// CatchUpOnce runs the task once for any number of missed runs.
+	CatchUpOnce
+	// CatchUpAll runs the task once for every missed run, back to back.
+	CatchUpAll
+)
+
+// maxCatchUp bounds how many missed runs CatchUpAll replays.
+const maxCatchUp = 100
+
+type ScheduleOptions struct {
+	// Spec is a cron expression, see ParseCron.
+	Spec string
+	// Location overrides the time zone of Spec.
+	Location *time.Location
+	// Jitter delays every run by a random duration up to Jitter.
+	Jitter  time.Duration
+	CatchUp CatchUpPolicy
+	// Since is the last known run, e.g. loaded from the run history. Runs
+	// scheduled between Since and the start of the scheduler count as missed.
+	Since time.Time
+	Args  []string
+}
+
+type scheduleEntry struct {
+	task     string
+	opts     ScheduleOptions
+	schedule *CronSchedule
+}
+
+// Scheduler runs registered tasks on cron schedules in process. Every entry
+// runs sequentially, so a task never overlaps with its previous run; combine
+// with Invoker.Locks to prevent overlaps across processes.
+type Scheduler struct {
+	// Now returns the current time, time.Now when nil. Tests pin it to check
+	// the catch-up deterministically.
+	Now     func() time.Time
+	invoker *Invoker
+	entries []*scheduleEntry
+}
+
+func NewScheduler(invoker *Invoker) *Scheduler {
+	return &Scheduler{invoker: invoker}
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+func (s *Scheduler) Schedule(task string, opts ScheduleOptions) error {
+	if _, err := s.invoker.Task(task); err != nil {
+		return err
+	}
+
+	schedule, err := ParseCron(opts.Spec)
+
+	if err != nil {
+		return err
+	}
+
+	if opts.Location != nil {
+		schedule.Location = opts.Location
+	}
+
+	s.entries = append(s.entries, &scheduleEntry{task: task, opts: opts, schedule: schedule})
+
+	return nil
+}
+
+// Run blocks until ctx is done, then waits up to Invoker.GracePeriod for the
+// running tasks to return.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for _, entry := range s.entries {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, entry)
+		}()
+	}
+
+	<-ctx.Done()
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(s.invoker.GracePeriod):
+		return ErrGracePeriodExceeded
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, entry *scheduleEntry) {
+	logger := s.invoker.logger().With(slog.String("task", entry.task), slog.String("schedule", entry.opts.Spec))
+	next := entry.schedule.Next(s.now())
+
+	if !entry.opts.Since.IsZero() {
+		next = entry.schedule.Next(entry.opts.Since)
+	}
+
+	for !next.IsZero() {
+		runs := 1
+		checked := s.now()
+
+		if next.After(checked) {
+			wait := next.Sub(checked)
+
+			if entry.opts.Jitter > 0 {
+				wait += rand.N(entry.opts.Jitter)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			checked = next
+		} else {
+			// The slot passed while the previous run was going or before the
+			// scheduler started.
+			overdue := entry.overdue(next, checked)
+
+			switch entry.opts.CatchUp {
+			case CatchUpSkip:
+				runs = 0
+				logger.Warn("skipping missed runs", slog.Int("missed", overdue))
+			case CatchUpOnce:
+				runs = 1
+			case CatchUpAll:
+				runs = min(overdue, maxCatchUp)
+			}
+		}
+
+		for range runs {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err := s.invoker.runTask(ctx, entry.task, entry.opts.Args); err != nil {
+				logger.Error("scheduled run failed", slog.Any("error", err))
+			}
+		}
+
+		next = entry.schedule.Next(checked)
+	}
+}
+
+// overdue counts the activations from due up to now.
+func (e *scheduleEntry) overdue(due time.Time, now time.Time) int {
+	count := 0
+
+	for t := due; !t.IsZero() && !t.After(now) && count < maxCatchUp; t = e.schedule.Next(t) {
+		count++
+	}
+
+	return count
+}
+
+// ServeCommand returns the long lived "serve" subcommand running the
+// scheduler until SIGINT or SIGTERM.
+func ServeCommand(scheduler *Scheduler) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Run the scheduled tasks until interrupted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return scheduler.Run(ctx)
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package task_invoker_test
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduler", func() {
+	var (
+		runs      atomic.Int32
+		invoker   *task_invoker.Invoker
+		scheduler *task_invoker.Scheduler
+	)
+
+	BeforeEach(func() {
+		runs.Store(0)
+
+		invoker = task_invoker.NewInvoker("", &cobra.Command{}, nil)
+		invoker.Logger = slog.New(slog.DiscardHandler)
+		invoker.GracePeriod = time.Second
+		invoker.Add("report", func() error {
+			runs.Add(1)
+			return nil
+		})
+
+		scheduler = task_invoker.NewScheduler(invoker)
+	})
+
+	run := func(ctx context.Context, duration time.Duration) error {
+		ctx, cancel := context.WithTimeout(ctx, duration)
+		defer cancel()
+
+		return scheduler.Run(ctx)
+	}
+
+	It("rejects unknown tasks and invalid specs", func() {
+		Expect(scheduler.Schedule("reprot", task_invoker.ScheduleOptions{Spec: "* * * * * *"})).
+			To(MatchError(ContainSubstring("did you mean: report")))
+		Expect(scheduler.Schedule("report", task_invoker.ScheduleOptions{Spec: "nope"})).To(HaveOccurred())
+	})
+
+	It("runs the task on schedule", func(ctx SpecContext) {
+		Expect(scheduler.Schedule("report", task_invoker.ScheduleOptions{Spec: "* * * * * *"})).To(Succeed())
+
+		Expect(run(ctx, 1100*time.Millisecond)).To(Succeed())
+		Expect(runs.Load()).To(BeNumerically(">=", 1))
+	})
+
+	DescribeTable("catches up the missed runs",
+		func(ctx SpecContext, policy task_invoker.CatchUpPolicy, expected int) {
+			now := time.Date(2025, 7, 1, 12, 0, 30, 0, time.UTC)
+			scheduler.Now = func() time.Time { return now }
+
+			Expect(scheduler.Schedule("report", task_invoker.ScheduleOptions{
+				Spec:    "0 * * * * *",
+				CatchUp: policy,
+				Since:   now.Add(-5 * time.Minute),
+			})).To(Succeed())
+
+			Expect(run(ctx, 100*time.Millisecond)).To(Succeed())
+			Expect(runs.Load()).To(Equal(int32(expected)))
+		},
+		Entry("skip", task_invoker.CatchUpSkip, 0),
+		Entry("once", task_invoker.CatchUpOnce, 1),
+		Entry("all", task_invoker.CatchUpAll, 5),
+	)
+
+	It("runs scheduled tasks without the process arguments", func(ctx SpecContext) {
+		setProcessArgs("serve")
+
+		invoker.AddTask(task_invoker.Task{
+			Name:  "digest",
+			Flags: func(flags *pflag.FlagSet) { flags.Bool("weekly", false, "send the weekly digest") },
+			Run: func(*task_invoker.TaskContext) error {
+				runs.Add(1)
+				return nil
+			},
+		})
+
+		now := time.Date(2025, 7, 1, 12, 0, 30, 0, time.UTC)
+		scheduler.Now = func() time.Time { return now }
+
+		Expect(scheduler.Schedule("digest", task_invoker.ScheduleOptions{
+			Spec:    "0 * * * * *",
+			CatchUp: task_invoker.CatchUpOnce,
+			Since:   now.Add(-time.Minute),
+		})).To(Succeed())
+
+		Expect(run(ctx, 100*time.Millisecond)).To(Succeed())
+		Expect(runs.Load()).To(Equal(int32(1)))
+	})
+
+	It("exposes a serve command", func() {
+		cmd := task_invoker.ServeCommand(scheduler)
+
+		Expect(cmd.Use).To(Equal("serve"))
+	})
+})
@@ -0,0 +1,135 @@
+package task_invoker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	ExitSuccess   = 0
+	ExitFailure   = 1
+	ExitTimeout   = 124
+	ExitCancelled = 130
+)
+
+var ErrGracePeriodExceeded = errors.New("task did not stop within the grace period")
+
+// ExitCoder lets an error choose the process exit code returned by ExitCode.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// SignalError is returned when a handled signal cancelled the task. Err is
+// what the task returned, or ErrGracePeriodExceeded when it did not return in
+// time.
+type SignalError struct {
+	Signal os.Signal
+	Err    error
+}
+
+func (e *SignalError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("interrupted by %s", e.Signal)
+	}
+
+	return fmt.Sprintf("interrupted by %s: %s", e.Signal, e.Err.Error())
+}
+
+func (e *SignalError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{context.Canceled}
+	}
+
+	return []error{context.Canceled, e.Err}
+}
+
+// ExitCode follows the shell convention of 128 + signal number.
+func (e *SignalError) ExitCode() int {
+	if sig, ok := e.Signal.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+
+	return ExitCancelled
+}
+
+// ExitCode maps the error returned by Run to a process exit code, telling
+// cancellation and timeouts apart from failures.
+func ExitCode(err error) int {
+	var coder ExitCoder
+
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case errors.As(err, &coder):
+		return coder.ExitCode()
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	case errors.Is(err, context.Canceled):
+		return ExitCancelled
+	}
+
+	return ExitFailure
+}
+
+// withSignals runs fn, cancelling its context on the first handled signal.
+// The handling then stops so a second signal, e.g. another Ctrl-C, gets the
+// default behaviour and kills a process stuck in its grace period.
+func (i *Invoker) withSignals(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	if len(i.Signals) == 0 || i.ignoresContext(name) {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, i.Signals...)
+	defer signal.Stop(signals)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case sig := <-signals:
+		signal.Stop(signals)
+		cancel()
+
+		grace := time.NewTimer(i.GracePeriod)
+		defer grace.Stop()
+
+		select {
+		case err := <-done:
+			return &SignalError{Signal: sig, Err: err}
+		case <-grace.C:
+			return &SignalError{Signal: sig, Err: ErrGracePeriodExceeded}
+		}
+	}
+}
+
+// ignoresContext reports whether name or one of its dependencies was added
+// with Add and cannot be cancelled.
+func (i *Invoker) ignoresContext(name string) bool {
+	graph, err := i.resolve([]string{name})
+
+	if err != nil {
+		return false
+	}
+
+	for _, task := range graph {
+		if task.ignoresContext {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+package task_invoker_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Nuanu-com/go-utils/task_invoker"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Context aware tasks", func() {
+	newInvoker := func(name string) *task_invoker.Invoker {
+		invoker := task_invoker.NewInvoker(name, &cobra.Command{}, nil)
+		invoker.Signals = []os.Signal{syscall.SIGUSR1}
+		invoker.GracePeriod = 50 * time.Millisecond
+
+		return invoker
+	}
+
+	It("passes the task context to context functions", func(ctx SpecContext) {
+		invoker := newInvoker("ctx")
+		invoker.AddContext("ctx", func(ctx context.Context) error {
+			tc, ok := task_invoker.TaskContextFrom(ctx)
+			Expect(ok).To(BeTrue())
+			Expect(tc.Name).To(Equal("ctx"))
+			return nil
+		})
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+	})
+
+	It("cancels the task on timeout", func(ctx SpecContext) {
+		invoker := newInvoker("slow")
+		invoker.AddTask(task_invoker.Task{
+			Name:    "slow",
+			Timeout: 10 * time.Millisecond,
+			Run: func(tc *task_invoker.TaskContext) error {
+				<-tc.Done()
+				return tc.Err()
+			},
+		})
+
+		err := invoker.RunContext(ctx)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(task_invoker.ExitCode(err)).To(Equal(task_invoker.ExitTimeout))
+	})
+
+	It("cancels the task on a handled signal", func(ctx SpecContext) {
+		invoker := newInvoker("wait")
+		started := make(chan struct{})
+		invoker.AddContext("wait", func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return nil
+		})
+
+		go func() {
+			<-started
+			syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+		}()
+
+		err := invoker.RunContext(ctx)
+
+		var signalErr *task_invoker.SignalError
+		Expect(errors.As(err, &signalErr)).To(BeTrue())
+		Expect(signalErr.Signal).To(Equal(syscall.SIGUSR1))
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(task_invoker.ExitCode(err)).To(Equal(128 + int(syscall.SIGUSR1)))
+	})
+
+	It("gives up after the grace period", func(ctx SpecContext) {
+		invoker := newInvoker("stuck")
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+
+		invoker.AddContext("stuck", func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		go func() {
+			<-started
+			syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+		}()
+
+		Expect(invoker.RunContext(ctx)).To(MatchError(task_invoker.ErrGracePeriodExceeded))
+	})
+
+	It("leaves the signals alone for tasks added without a context", func(ctx SpecContext) {
+		received := make(chan os.Signal, 1)
+		signal.Notify(received, syscall.SIGUSR1)
+		defer signal.Stop(received)
+
+		invoker := newInvoker("legacy")
+		invoker.Add("legacy", func() error {
+			syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+			<-received
+			return nil
+		})
+
+		Expect(invoker.RunContext(ctx)).To(Succeed())
+	})
+
+	It("maps errors to exit codes", func() {
+		Expect(task_invoker.ExitCode(nil)).To(Equal(task_invoker.ExitSuccess))
+		Expect(task_invoker.ExitCode(errors.New("boom"))).To(Equal(task_invoker.ExitFailure))
+		Expect(task_invoker.ExitCode(context.Canceled)).To(Equal(task_invoker.ExitCancelled))
+		Expect(task_invoker.ExitCode(&task_invoker.SignalError{Signal: os.Interrupt})).To(Equal(task_invoker.ExitCancelled))
+	})
+})
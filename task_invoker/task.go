@@ -0,0 +1,192 @@
+package task_invoker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type TaskFN func(tc *TaskContext) error
+
+type TaskArg struct {
+	Name        string
+	Description string
+	// Optional arguments must come after the required ones.
+	Optional bool
+}
+
+type Task struct {
+	Name        string
+	Description string
+	// Args declares the positional arguments. A task without Args and Flags
+	// receives the raw arguments without any parsing.
+	Args []TaskArg
+	// Flags declares the typed flags on the pflag set used by cobra.
+	Flags func(flags *pflag.FlagSet)
+	// DependsOn names the tasks that must succeed before this one runs.
+	DependsOn []string
+	// Timeout cancels the task context of every attempt after the duration.
+	// Zero disables it.
+	Timeout time.Duration
+	// Retry reruns the task on failure. Nil runs it once.
+	Retry *RetryPolicy
+	// LockTTL is how long the lock taken in Invoker.Locks is held before it is
+	// considered stale. Zero uses DefaultLockTTL.
+	LockTTL time.Duration
+	// Middleware wraps the task inside the middleware of Invoker.Use.
+	Middleware []Middleware
+	// ReplaceMiddleware skips the middleware of Invoker.Use for this task.
+	ReplaceMiddleware bool
+	Run               TaskFN
+	// ignoresContext marks the tasks registered with Add.
+	ignoresContext bool
+}
+
+// TaskContext is handed to a running task with its parsed arguments. It is
+// the task context.Context, cancelled on timeout or on a handled signal.
+type TaskContext struct {
+	context.Context
+	Name  string
+	Args  []string
+	Flags *pflag.FlagSet
+	Out   io.Writer
+	// Job is the queued job running the task, nil outside a WorkerPool.
+	Job  *Job
+	task *Task
+	// result is shared by the copies made for every attempt.
+	result *taskResult
+}
+
+type taskResult struct {
+	value any
+}
+
+type taskContextKey struct{}
+
+// TaskContextFrom returns the TaskContext carried by ctx, which is how an
+// InvokerCtxFN reaches its arguments.
+func TaskContextFrom(ctx context.Context) (*TaskContext, bool) {
+	tc, ok := ctx.Value(taskContextKey{}).(*TaskContext)
+
+	return tc, ok
+}
+
+func (tc *TaskContext) Value(key any) any {
+	if _, ok := key.(taskContextKey); ok {
+		return tc
+	}
+
+	return tc.Context.Value(key)
+}
+
+// SetResult attaches a summary of what the task produced to its run record.
+// It must be JSON serializable.
+func (tc *TaskContext) SetResult(summary any) {
+	tc.result.value = summary
+}
+
+// Arg returns the positional argument declared with name, or an empty string
+// when an optional argument was not given.
+func (tc *TaskContext) Arg(name string) string {
+	for idx, arg := range tc.task.Args {
+		if arg.Name == name && idx < len(tc.Args) {
+			return tc.Args[idx]
+		}
+	}
+
+	return ""
+}
+
+func (t *Task) rawArgs() bool {
+	return t.Args == nil && t.Flags == nil
+}
+
+func (t *Task) usage() string {
+	parts := []string{t.Name}
+
+	if t.Flags != nil {
+		parts = append(parts, "[flags]")
+	}
+
+	for _, arg := range t.Args {
+		if arg.Optional {
+			parts = append(parts, fmt.Sprintf("[%s]", arg.Name))
+		} else {
+			parts = append(parts, fmt.Sprintf("<%s>", arg.Name))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (t *Task) long() string {
+	if len(t.Args) == 0 {
+		return t.Description
+	}
+
+	lines := []string{t.Description, "", "Arguments:"}
+
+	for _, arg := range t.Args {
+		lines = append(lines, fmt.Sprintf("  %-16s %s", arg.Name, arg.Description))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (t *Task) validateArgs(cmd *cobra.Command, args []string) error {
+	if t.rawArgs() {
+		return nil
+	}
+
+	required := 0
+
+	for _, arg := range t.Args {
+		if !arg.Optional {
+			required++
+		}
+	}
+
+	return cobra.RangeArgs(required, len(t.Args))(cmd, args)
+}
+
+// command builds the cobra command parsing the task arguments and handing the
+// resulting TaskContext to run. A nil out inherits the output of the parent
+// command.
+func (t *Task) command(out io.Writer, run func(tc *TaskContext) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                t.usage(),
+		Short:              t.Description,
+		Long:               t.long(),
+		Args:               t.validateArgs,
+		DisableFlagParsing: t.rawArgs(),
+		SilenceErrors:      true,
+		SilenceUsage:       true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(&TaskContext{
+				Context: cmd.Context(),
+				Name:    t.Name,
+				Args:    args,
+				Flags:   cmd.Flags(),
+				Out:     cmd.OutOrStdout(),
+				task:    t,
+				result:  &taskResult{},
+			})
+		},
+	}
+
+	if t.Flags != nil {
+		t.Flags(cmd.Flags())
+	}
+
+	if out != nil {
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+	}
+
+	return cmd
+}
@@ -0,0 +1,23 @@
+package task_invoker_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTaskInvoker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TaskInvoker Suite")
+}
+
+// setProcessArgs replaces the process arguments for the spec, so tasks run
+// without arguments would fail if cobra fell back to os.Args.
+func setProcessArgs(args ...string) {
+	saved := os.Args
+	os.Args = append([]string{"tool"}, args...)
+
+	DeferCleanup(func() { os.Args = saved })
+}
@@ -0,0 +1,171 @@
+package task_invoker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var ErrNoPayload = errors.New("task is not running from a job")
+
+type WorkerOptions struct {
+	// Concurrency is the number of jobs processed at once. Values below 1
+	// use 1.
+	Concurrency int
+	// PollInterval is how long an idle worker waits before polling again.
+	// Zero uses one second.
+	PollInterval time.Duration
+	// VisibilityTimeout hides a dequeued job from the other workers. A job
+	// still running past it may run twice, keep Task.Timeout below it. Zero
+	// uses five minutes.
+	VisibilityTimeout time.Duration
+	// Backoff delays the retries of failed jobs. Nil backs off exponentially
+	// from one second up to one hour.
+	Backoff *RetryPolicy
+}
+
+// WorkerPool processes the jobs of a Queue with the registered tasks. A
+// failed job is retried until its MaxAttempts, then moved to the dead
+// letters, as are jobs of unknown tasks.
+type WorkerPool struct {
+	invoker *Invoker
+	queue   Queue
+	opts    WorkerOptions
+}
+
+func NewWorkerPool(invoker *Invoker, queue Queue, opts WorkerOptions) *WorkerPool {
+	opts.Concurrency = max(opts.Concurrency, 1)
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = 5 * time.Minute
+	}
+
+	if opts.Backoff == nil {
+		opts.Backoff = &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Hour, Jitter: 0.1}
+	}
+
+	return &WorkerPool{invoker: invoker, queue: queue, opts: opts}
+}
+
+// Run processes jobs until ctx is done, then waits up to Invoker.GracePeriod
+// for the running jobs to return. Jobs interrupted by the shutdown are
+// released to run again.
+func (p *WorkerPool) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for range p.opts.Concurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			p.loop(ctx)
+		}()
+	}
+
+	<-ctx.Done()
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(p.invoker.GracePeriod):
+		return ErrGracePeriodExceeded
+	}
+}
+
+func (p *WorkerPool) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := p.queue.Dequeue(ctx, p.opts.VisibilityTimeout)
+
+		if err != nil {
+			if !errors.Is(err, ErrNoJobs) && ctx.Err() == nil {
+				p.invoker.logger().Error("failed to dequeue job", slog.Any("error", err))
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(p.opts.PollInterval):
+			}
+
+			continue
+		}
+
+		p.process(ctx, job)
+	}
+}
+
+// process runs one job and settles it in the queue. The queue updates ignore
+// the shutdown so an interrupted job is not left hidden.
+func (p *WorkerPool) process(ctx context.Context, job *Job) {
+	logger := p.invoker.logger().With(slog.String("job_id", job.ID), slog.String("task", job.Task), slog.Int("attempt", job.Attempts))
+	settleCtx := context.WithoutCancel(ctx)
+	err := p.runJob(ctx, job)
+
+	var unknown *UnknownTaskError
+
+	switch {
+	case err == nil:
+		err = p.queue.Complete(settleCtx, job)
+	case ctx.Err() != nil:
+		logger.Warn("job interrupted by shutdown", slog.Any("error", err))
+		err = p.queue.Retry(settleCtx, job, err, time.Now())
+	case errors.As(err, &unknown) || job.Attempts >= job.MaxAttempts:
+		logger.Error("job moved to the dead letters", slog.Any("error", err))
+		err = p.queue.Kill(settleCtx, job, err)
+	default:
+		backoff := p.opts.Backoff.Backoff(job.Attempts + 1)
+		logger.Warn("job failed, retrying", slog.Any("error", err), slog.Duration("backoff", backoff))
+		err = p.queue.Retry(settleCtx, job, err, time.Now().Add(backoff))
+	}
+
+	if err != nil {
+		logger.Error("failed to settle job", slog.Any("error", err))
+	}
+}
+
+// runJob runs the job task like RunContext, after its dependencies, with the
+// job attached to the TaskContext.
+func (p *WorkerPool) runJob(ctx context.Context, job *Job) error {
+	task, err := p.invoker.Task(job.Task)
+
+	if err != nil {
+		return err
+	}
+
+	run := func(ctx context.Context) error {
+		cmd := task.command(p.invoker.out(), func(tc *TaskContext) error {
+			tc.Job = job
+			return p.invoker.invoke(tc)
+		})
+
+		return runCommand(ctx, cmd, job.Args)
+	}
+
+	if len(task.DependsOn) > 0 {
+		return p.invoker.runGraph(ctx, task.Name, run)
+	}
+
+	return run(ctx)
+}
+
+// Payload decodes the payload of the job running the task into target.
+func (tc *TaskContext) Payload(target any) error {
+	if tc.Job == nil {
+		return ErrNoPayload
+	}
+
+	return json.Unmarshal(tc.Job.Payload, target)
+}
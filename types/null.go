@@ -192,3 +192,37 @@ func MapNull[T, R any](data Null[T], predicate func(data T) Null[R]) Null[R] {
 
 	return Null[R]{}
 }
+
+// NullsFirst builds a comparator for Null[T] that sorts invalid values before
+// valid ones and compares valid values with compare. It plugs into
+// slice_utils.ByFunc.
+func NullsFirst[T any](compare func(a, b T) int) func(a, b Null[T]) int {
+	return func(a, b Null[T]) int {
+		switch {
+		case !a.Valid && !b.Valid:
+			return 0
+		case !a.Valid:
+			return -1
+		case !b.Valid:
+			return 1
+		}
+
+		return compare(a.V, b.V)
+	}
+}
+
+// NullsLast is NullsFirst with invalid values sorted after valid ones.
+func NullsLast[T any](compare func(a, b T) int) func(a, b Null[T]) int {
+	return func(a, b Null[T]) int {
+		switch {
+		case !a.Valid && !b.Valid:
+			return 0
+		case !a.Valid:
+			return 1
+		case !b.Valid:
+			return -1
+		}
+
+		return compare(a.V, b.V)
+	}
+}
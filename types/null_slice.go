@@ -0,0 +1,19 @@
+package types
+
+import "github.com/Nuanu-com/go-utils/slice_utils"
+
+// GetNull is slice_utils.Get returning a Null[T] that is invalid when idx is
+// out of range. Negative indexes count from the end.
+func GetNull[T any](s []T, idx int) Null[T] {
+	v, ok := slice_utils.Get(s, idx)
+
+	return NewNull(v, ok)
+}
+
+func FirstNull[T any](s []T) Null[T] {
+	return GetNull(s, 0)
+}
+
+func LastNull[T any](s []T) Null[T] {
+	return GetNull(s, -1)
+}
@@ -0,0 +1,26 @@
+package types_test
+
+import (
+	"github.com/Nuanu-com/go-utils/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetNull", func() {
+	s := []string{"a", "b", "c"}
+
+	It("returns a valid Null for existing indexes", func() {
+		Expect(types.GetNull(s, 1)).To(Equal(types.NewNull("b", true)))
+		Expect(types.GetNull(s, -1)).To(Equal(types.NewNull("c", true)))
+		Expect(types.FirstNull(s)).To(Equal(types.NewNull("a", true)))
+		Expect(types.LastNull(s)).To(Equal(types.NewNull("c", true)))
+	})
+
+	It("returns an invalid Null when out of range", func() {
+		Expect(types.GetNull(s, 3).Valid).To(BeFalse())
+		Expect(types.GetNull(s, -4).Valid).To(BeFalse())
+		Expect(types.FirstNull([]string{}).Valid).To(BeFalse())
+		Expect(types.LastNull([]string(nil)).Valid).To(BeFalse())
+	})
+})
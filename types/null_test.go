@@ -1,9 +1,11 @@
 package types_test
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 
+	"github.com/Nuanu-com/go-utils/slice_utils"
 	"github.com/Nuanu-com/go-utils/types"
 
 	"github.com/google/uuid"
@@ -373,3 +375,23 @@ var _ = Describe("MapNull", func() {
 		Expect(data2).To(Equal(types.NewNull("", false)))
 	})
 })
+
+var _ = Describe("NullsFirst and NullsLast", func() {
+	values := []types.Null[int]{
+		types.NewNull(3, true),
+		types.NewNull(0, false),
+		types.NewNull(1, true),
+	}
+
+	It("sorts the invalid values first", func() {
+		result := slice_utils.SortBy(values, slice_utils.ByFunc(func(n types.Null[int]) types.Null[int] { return n }, types.NullsFirst(cmp.Compare[int])))
+
+		Expect(result).To(Equal([]types.Null[int]{values[1], values[2], values[0]}))
+	})
+
+	It("sorts the invalid values last", func() {
+		result := slice_utils.SortBy(values, types.NullsLast(cmp.Compare[int]))
+
+		Expect(result).To(Equal([]types.Null[int]{values[2], values[0], values[1]}))
+	})
+})
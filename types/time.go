@@ -75,6 +75,10 @@ func (t TimeOnly) MarshalJSON() ([]byte, error) {
 	return fmt.Appendf(nil, "\"%s\"", t.Format(HourMinuteSecondLayout)), nil
 }
 
+func (t TimeOnly) MarshalText() ([]byte, error) {
+	return []byte(t.Format(HourMinuteSecondLayout)), nil
+}
+
 func MustParseTimeOnly(data string) TimeOnly {
 	res, err := time.Parse(HourMinuteSecondLayout, data)
 